@@ -3,6 +3,7 @@ package mkt
 import (
 	"fmt"
 
+	"github.com/gbkr-com/utl"
 	"github.com/shopspring/decimal"
 )
 
@@ -13,6 +14,8 @@ type Book[T AnyListing] struct {
 	whitelist *WhiteList[T]
 	positions map[string]*Position[T]
 	c         chan *PositionMemo
+	pool      *utl.Pool[*PositionMemo]
+	rolls     []*Roll
 }
 
 // BookOption is any option that can be applied when constructing the book.
@@ -26,6 +29,14 @@ func WithBookChannel[T AnyListing](c chan *PositionMemo) BookOption[T] {
 	}
 }
 
+// WithBookPool takes each [*PositionMemo] written to the channel from the
+// pool. See [WithPositionPool].
+func WithBookPool[T AnyListing](pool *utl.Pool[*PositionMemo]) BookOption[T] {
+	return func(book *Book[T]) {
+		book.pool = pool
+	}
+}
+
 // NewBook returns a [*Book] ready to use.
 func NewBook[T AnyListing](name string, whitelist *WhiteList[T], options ...BookOption[T]) *Book[T] {
 	book := &Book[T]{name: name, whitelist: whitelist, positions: map[string]*Position[T]{}}
@@ -67,6 +78,52 @@ func (x *Book[T]) Traded(symbol string, side Side, lastQty decimal.Decimal, last
 
 }
 
+// TradedWithID applies the trade to the book as [Book.Traded], keeping the
+// trade ID so the trade can later be cancelled or corrected. See
+// [Position.TradedWithID].
+func (x *Book[T]) TradedWithID(symbol, tradeID string, side Side, lastQty decimal.Decimal, lastPx decimal.Decimal) error {
+
+	position := x.positions[symbol]
+	if position == nil {
+		var err error
+		position, err = x.makePosition(symbol)
+		if err != nil {
+			return err
+		}
+	}
+
+	return position.TradedWithID(tradeID, side, lastQty, lastPx)
+
+}
+
+// Cancel removes the trade from the position in the symbol. See
+// [Position.Cancel].
+func (x *Book[T]) Cancel(symbol, tradeID string) error {
+	position := x.positions[symbol]
+	if position == nil {
+		return fmt.Errorf("mkt.Book: no position in %s", symbol)
+	}
+	return position.Cancel(tradeID)
+}
+
+// Correct amends the trade in the position in the symbol. See
+// [Position.Correct].
+func (x *Book[T]) Correct(symbol, tradeID string, lastQty decimal.Decimal, lastPx decimal.Decimal) error {
+	position := x.positions[symbol]
+	if position == nil {
+		return fmt.Errorf("mkt.Book: no position in %s", symbol)
+	}
+	return position.Correct(tradeID, lastQty, lastPx)
+}
+
+// Compact forgets the history of every position in the book. See
+// [Position.Compact].
+func (x *Book[T]) Compact() {
+	for _, position := range x.positions {
+		position.Compact()
+	}
+}
+
 func (x *Book[T]) makePosition(symbol string) (*Position[T], error) {
 
 	_, ok := x.whitelist.Lookup(symbol)
@@ -78,6 +135,9 @@ func (x *Book[T]) makePosition(symbol string) (*Position[T], error) {
 	if x.c != nil {
 		options = append(options, WithPositionChannel[T](x.c))
 	}
+	if x.pool != nil {
+		options = append(options, WithPositionPool[T](x.pool))
+	}
 
 	position := NewPosition(symbol, x.whitelist, options...)
 	x.positions[symbol] = position
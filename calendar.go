@@ -0,0 +1,319 @@
+package mkt
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// The Phase of a trading session.
+type Phase int64
+
+// Recognised Phase values.
+const (
+	PhaseClosed     Phase = 0
+	PhasePreOpen    Phase = 1
+	PhaseAuction    Phase = 2
+	PhaseContinuous Phase = 3
+)
+
+func (x Phase) String() string {
+	switch x {
+	case PhaseClosed:
+		return "CLOSED"
+	case PhasePreOpen:
+		return "PREOPEN"
+	case PhaseAuction:
+		return "AUCTION"
+	case PhaseContinuous:
+		return "CONTINUOUS"
+	default:
+		return ""
+	}
+}
+
+// PhaseFromString returns a recognised [Phase] or zero, which is
+// [PhaseClosed].
+func PhaseFromString(s string) Phase {
+	switch s {
+	case "PREOPEN":
+		return PhasePreOpen
+	case "AUCTION":
+		return PhaseAuction
+	case "CONTINUOUS":
+		return PhaseContinuous
+	default:
+		return PhaseClosed
+	}
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (x Phase) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.String())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (x *Phase) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*x = PhaseFromString(s)
+	return nil
+}
+
+// Open returns true if orders can trade in the phase, which is either an
+// auction or continuous trading.
+func (x Phase) Open() bool {
+	return x == PhaseAuction || x == PhaseContinuous
+}
+
+// Session is one phase of a trading day, from Start up to End. Both are
+// offsets in wall clock time from local midnight, so 8h is 08:00 whatever the
+// daylight saving.
+type Session struct {
+	Phase Phase         `json:"phase"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+}
+
+// Calendar is the trading hours of a [Listing] or of a whole venue. Any time
+// outside a [Session], and all of any weekend day or holiday, is
+// [PhaseClosed].
+//
+// A Calendar is immutable once constructed, so may be shared between
+// listings.
+type Calendar struct {
+	location *time.Location
+	regular  []Session
+	half     []Session
+	weekend  [7]bool
+	holidays map[date]bool
+	halfDays map[date]bool
+}
+
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func dateOf(t time.Time) date {
+	y, m, d := t.Date()
+	return date{y, m, d}
+}
+
+// CalendarOption is any option that can be applied when constructing the
+// calendar.
+type CalendarOption func(*Calendar)
+
+// WithWeekend sets the days on which there is no trading. The default is
+// Saturday and Sunday.
+func WithWeekend(days ...time.Weekday) CalendarOption {
+	return func(calendar *Calendar) {
+		calendar.weekend = [7]bool{}
+		for _, day := range days {
+			calendar.weekend[day] = true
+		}
+	}
+}
+
+// WithHolidays adds dates on which there is no trading. Only the date of each
+// time is used.
+func WithHolidays(dates ...time.Time) CalendarOption {
+	return func(calendar *Calendar) {
+		for _, t := range dates {
+			calendar.holidays[dateOf(t)] = true
+		}
+	}
+}
+
+// WithHalfDays adds dates on which the half day sessions apply instead of the
+// regular sessions. Only the date of each time is used.
+func WithHalfDays(sessions []Session, dates ...time.Time) CalendarOption {
+	return func(calendar *Calendar) {
+		calendar.half = sessions
+		for _, t := range dates {
+			calendar.halfDays[dateOf(t)] = true
+		}
+	}
+}
+
+// NewCalendar returns a [*Calendar] in the time zone with the regular
+// sessions of a trading day. Sessions must be in time order, within the day,
+// and not overlap.
+func NewCalendar(location *time.Location, sessions []Session, options ...CalendarOption) (*Calendar, error) {
+
+	if location == nil {
+		return nil, errors.New("mkt.NewCalendar: nil location")
+	}
+
+	calendar := &Calendar{
+		location: location,
+		regular:  sessions,
+		holidays: map[date]bool{},
+		halfDays: map[date]bool{},
+	}
+	calendar.weekend[time.Saturday] = true
+	calendar.weekend[time.Sunday] = true
+	for _, option := range options {
+		option(calendar)
+	}
+
+	if err := validateSessions(calendar.regular); err != nil {
+		return nil, fmt.Errorf("mkt.NewCalendar: %w", err)
+	}
+	if err := validateSessions(calendar.half); err != nil {
+		return nil, fmt.Errorf("mkt.NewCalendar: half day: %w", err)
+	}
+	calendar.regular = append([]Session(nil), calendar.regular...)
+	calendar.half = append([]Session(nil), calendar.half...)
+	return calendar, nil
+
+}
+
+func validateSessions(sessions []Session) error {
+	var end time.Duration
+	for i, session := range sessions {
+		if session.Start < end || session.End <= session.Start || session.End > 24*time.Hour {
+			return fmt.Errorf("session %d: %s to %s is out of order", i, session.Start, session.End)
+		}
+		end = session.End
+	}
+	return nil
+}
+
+// Location returns the time zone of the calendar.
+func (x *Calendar) Location() *time.Location {
+	return x.location
+}
+
+// Sessions returns the sessions on the local date of the time, which are
+// none on a weekend or holiday.
+func (x *Calendar) Sessions(t time.Time) []Session {
+	d := dateOf(t.In(x.location))
+	if x.holidays[d] || x.weekend[time.Date(d.year, d.month, d.day, 0, 0, 0, 0, time.UTC).Weekday()] {
+		return nil
+	}
+	if x.halfDays[d] {
+		return x.half
+	}
+	return x.regular
+}
+
+// Phase returns the phase at the time.
+func (x *Calendar) Phase(t time.Time) Phase {
+	for _, session := range x.Sessions(t) {
+		start, end := x.bounds(t, session)
+		if !t.Before(start) && t.Before(end) {
+			return session.Phase
+		}
+	}
+	return PhaseClosed
+}
+
+// IsOpen returns true if orders can trade at the time.
+func (x *Calendar) IsOpen(t time.Time) bool {
+	return x.Phase(t).Open()
+}
+
+// NextOpen returns the time at which orders can next trade: the time itself if
+// already open, otherwise the start of the next open session. It returns the
+// zero time if there is no open session within a year.
+func (x *Calendar) NextOpen(t time.Time) time.Time {
+	day := t.In(x.location)
+	for i := 0; i <= 366; i++ {
+		for _, session := range x.Sessions(day) {
+			if !session.Phase.Open() {
+				continue
+			}
+			start, end := x.bounds(day, session)
+			if !end.After(t) {
+				continue
+			}
+			if start.Before(t) {
+				return t
+			}
+			return start
+		}
+		y, m, d := day.Date()
+		day = time.Date(y, m, d+1, 0, 0, 0, 0, x.location)
+	}
+	return time.Time{}
+}
+
+// TimeToClose returns the time left in which orders can trade, across any
+// contiguous open sessions, or zero if closed at the time.
+func (x *Calendar) TimeToClose(t time.Time) time.Duration {
+	var until time.Time
+	for _, session := range x.Sessions(t) {
+		start, end := x.bounds(t, session)
+		switch {
+		case !session.Phase.Open():
+			if !until.IsZero() {
+				return until.Sub(t)
+			}
+		case !until.IsZero() && start.Equal(until):
+			until = end
+		case !t.Before(start) && t.Before(end):
+			until = end
+		case !until.IsZero():
+			return until.Sub(t)
+		}
+	}
+	if until.IsZero() {
+		return 0
+	}
+	return until.Sub(t)
+}
+
+// bounds returns the start and end of the session on the local date of the
+// time.
+func (x *Calendar) bounds(t time.Time, session Session) (time.Time, time.Time) {
+	y, m, d := t.In(x.location).Date()
+	start := time.Date(y, m, d, 0, 0, 0, int(session.Start), x.location)
+	end := time.Date(y, m, d, 0, 0, 0, int(session.End), x.location)
+	return start, end
+}
+
+// ReadCalendarDays reads holidays and half days, one date per line in the
+// form 2006-01-02, for use with [WithHolidays] and [WithHalfDays]. A date
+// followed by ",half" is a half day. Blank lines and lines starting with '#'
+// are ignored. Errors report the line number.
+func ReadCalendarDays(r io.Reader) (holidays []time.Time, halfDays []time.Time, err error) {
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		value, kind, _ := strings.Cut(text, ",")
+		t, err := time.Parse(time.DateOnly, strings.TrimSpace(value))
+		if err != nil {
+			return nil, nil, fmt.Errorf("mkt.ReadCalendarDays: line %d: %w", line, err)
+		}
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "":
+			holidays = append(holidays, t)
+		case "half":
+			halfDays = append(halfDays, t)
+		default:
+			return nil, nil, fmt.Errorf("mkt.ReadCalendarDays: line %d: unknown kind %q", line, kind)
+		}
+
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("mkt.ReadCalendarDays: %w", err)
+	}
+	return holidays, halfDays, nil
+
+}
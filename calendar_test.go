@@ -0,0 +1,118 @@
+package mkt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCalendar(t *testing.T) *Calendar {
+
+	days := `
+# 2026
+2026-12-24,half
+2026-12-25
+2026-12-28
+`
+	holidays, halfDays, err := ReadCalendarDays(strings.NewReader(days))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(holidays))
+	assert.Equal(t, 1, len(halfDays))
+
+	calendar, err := NewCalendar(
+		time.FixedZone("CET", 3600),
+		[]Session{
+			{Phase: PhasePreOpen, Start: 7*time.Hour + 30*time.Minute, End: 8 * time.Hour},
+			{Phase: PhaseAuction, Start: 8 * time.Hour, End: 8*time.Hour + 5*time.Minute},
+			{Phase: PhaseContinuous, Start: 8*time.Hour + 5*time.Minute, End: 16*time.Hour + 30*time.Minute},
+			{Phase: PhaseAuction, Start: 16*time.Hour + 30*time.Minute, End: 16*time.Hour + 35*time.Minute},
+		},
+		WithHolidays(holidays...),
+		WithHalfDays(
+			[]Session{
+				{Phase: PhaseContinuous, Start: 8 * time.Hour, End: 12 * time.Hour},
+			},
+			halfDays...,
+		),
+	)
+	assert.Nil(t, err)
+	return calendar
+
+}
+
+func TestNewCalendar(t *testing.T) {
+
+	_, err := NewCalendar(nil, nil)
+	assert.NotNil(t, err)
+
+	_, err = NewCalendar(time.UTC, []Session{
+		{Phase: PhaseContinuous, Start: 8 * time.Hour, End: 12 * time.Hour},
+		{Phase: PhaseAuction, Start: 11 * time.Hour, End: 13 * time.Hour},
+	})
+	assert.NotNil(t, err)
+
+	_, _, err = ReadCalendarDays(strings.NewReader("2026-12-25\n2026-13-01\n"))
+	assert.ErrorContains(t, err, "line 2")
+
+}
+
+func TestCalendarPhase(t *testing.T) {
+
+	calendar := newTestCalendar(t)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 12, day, hour, minute, 0, 0, calendar.Location())
+	}
+
+	assert.Equal(t, PhaseClosed, calendar.Phase(at(23, 7, 0)))
+	assert.Equal(t, PhasePreOpen, calendar.Phase(at(23, 7, 45)))
+	assert.Equal(t, PhaseAuction, calendar.Phase(at(23, 8, 0)))
+	assert.Equal(t, PhaseContinuous, calendar.Phase(at(23, 12, 0)))
+	assert.Equal(t, PhaseAuction, calendar.Phase(at(23, 16, 30)))
+	assert.Equal(t, PhaseClosed, calendar.Phase(at(23, 16, 35)))
+
+	assert.True(t, calendar.IsOpen(at(23, 12, 0).UTC()), "time zone")
+	assert.False(t, calendar.IsOpen(at(23, 7, 0).UTC()))
+	assert.True(t, calendar.IsOpen(at(24, 11, 0)), "half day")
+	assert.False(t, calendar.IsOpen(at(24, 13, 0)), "half day")
+	assert.False(t, calendar.IsOpen(at(25, 12, 0)), "holiday")
+	assert.False(t, calendar.IsOpen(at(26, 12, 0)), "weekend")
+
+	assert.Equal(t, "AUCTION", PhaseAuction.String())
+	assert.Equal(t, PhasePreOpen, PhaseFromString("PREOPEN"))
+
+}
+
+func TestCalendarNextOpen(t *testing.T) {
+
+	calendar := newTestCalendar(t)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 12, day, hour, minute, 0, 0, calendar.Location())
+	}
+
+	assert.True(t, calendar.NextOpen(at(23, 12, 0)).Equal(at(23, 12, 0)))
+	assert.True(t, calendar.NextOpen(at(23, 7, 45)).Equal(at(23, 8, 0)))
+	assert.True(t, calendar.NextOpen(at(23, 17, 0)).Equal(at(24, 8, 0)))
+	assert.True(t, calendar.NextOpen(at(24, 13, 0)).Equal(at(29, 8, 0)), "holidays and weekend")
+
+	closed, err := NewCalendar(time.UTC, nil)
+	assert.Nil(t, err)
+	assert.True(t, closed.NextOpen(at(23, 12, 0)).IsZero())
+
+}
+
+func TestCalendarTimeToClose(t *testing.T) {
+
+	calendar := newTestCalendar(t)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 12, day, hour, minute, 0, 0, calendar.Location())
+	}
+
+	assert.Equal(t, 8*time.Hour+35*time.Minute, calendar.TimeToClose(at(23, 8, 0)))
+	assert.Equal(t, 35*time.Minute, calendar.TimeToClose(at(23, 16, 0)))
+	assert.Equal(t, time.Duration(0), calendar.TimeToClose(at(23, 7, 45)))
+	assert.Equal(t, time.Hour, calendar.TimeToClose(at(24, 11, 0)))
+	assert.Equal(t, time.Duration(0), calendar.TimeToClose(at(25, 11, 0)))
+
+}
@@ -0,0 +1,354 @@
+package mkt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CodecVersion is the version of the binary encoding written by the Append
+// functions, such as [AppendQuote].
+//
+// Each message is a frame: the length of the rest of the frame as a uvarint,
+// the version and [MessageType] as one byte each, then the fields in a fixed
+// order. Integers and enums are varints, strings are length prefixed, a
+// decimal is its exponent then its coefficient as varints, and a time is its
+// Unix nanoseconds as a varint with zero meaning the zero time. Decoding does
+// not use reflection.
+const CodecVersion = 1
+
+// MessageType identifies the type encoded in a frame.
+type MessageType byte
+
+// Recognised MessageType values.
+const (
+	MessageQuote        MessageType = 1
+	MessageTrade        MessageType = 2
+	MessageReport       MessageType = 3
+	MessagePositionMemo MessageType = 4
+)
+
+// MaxFrameSize is the largest frame length that will be decoded or read, so
+// that a corrupt or hostile length prefix cannot exhaust memory.
+const MaxFrameSize = 1 << 20
+
+// ErrShortFrame is returned when a frame is incomplete.
+var ErrShortFrame = errors.New("mkt: short frame")
+
+// ErrFrameTooLarge is returned when a frame length exceeds [MaxFrameSize].
+var ErrFrameTooLarge = errors.New("mkt: frame too large")
+
+// AppendQuote appends the quote as a frame to the buffer, which may be nil.
+// It returns an error only if a decimal has a coefficient too large for an
+// int64, or [ErrFrameTooLarge], in which case the buffer is unchanged.
+func AppendQuote(b []byte, quote *Quote) ([]byte, error) {
+	w := codecWriter{b: b}
+	w.begin(MessageQuote)
+	w.string(quote.Symbol)
+	w.decimal(quote.BidPx)
+	w.decimal(quote.BidSize)
+	w.decimal(quote.AskPx)
+	w.decimal(quote.AskSize)
+	return w.end()
+}
+
+// DecodeQuote decodes the frame at the start of the buffer into the quote,
+// returning the length of the frame.
+func DecodeQuote(b []byte, quote *Quote) (int, error) {
+	r, n, err := newCodecReader(b, MessageQuote)
+	if err != nil {
+		return 0, err
+	}
+	quote.Symbol = r.string()
+	quote.BidPx = r.decimal()
+	quote.BidSize = r.decimal()
+	quote.AskPx = r.decimal()
+	quote.AskSize = r.decimal()
+	return n, r.done()
+}
+
+// AppendTrade appends the trade as a frame to the buffer, as [AppendQuote].
+func AppendTrade(b []byte, trade *Trade) ([]byte, error) {
+	w := codecWriter{b: b}
+	w.begin(MessageTrade)
+	w.string(trade.Symbol)
+	w.decimal(trade.LastQty)
+	w.decimal(trade.LastPx)
+	w.decimal(trade.TradeVolume)
+	w.decimal(trade.AvgPx)
+	return w.end()
+}
+
+// DecodeTrade decodes a frame into the trade, as [DecodeQuote].
+func DecodeTrade(b []byte, trade *Trade) (int, error) {
+	r, n, err := newCodecReader(b, MessageTrade)
+	if err != nil {
+		return 0, err
+	}
+	trade.Symbol = r.string()
+	trade.LastQty = r.decimal()
+	trade.LastPx = r.decimal()
+	trade.TradeVolume = r.decimal()
+	trade.AvgPx = r.decimal()
+	return n, r.done()
+}
+
+// AppendReport appends the report as a frame to the buffer, as [AppendQuote].
+func AppendReport(b []byte, report *Report) ([]byte, error) {
+	w := codecWriter{b: b}
+	w.begin(MessageReport)
+	w.string(report.OrderID)
+	w.string(report.Symbol)
+	w.varint(int64(report.Side))
+	w.string(report.SecondaryOrderID)
+	w.string(report.ClOrdID)
+	w.varint(int64(report.OrdStatus))
+	w.string(report.Account)
+	w.varint(int64(report.TimeInForce))
+	w.decimal(report.LastQty)
+	w.decimal(report.LastPx)
+	w.time(report.TransactTime)
+	w.string(report.ExecInst)
+	w.string(report.ExecID)
+	w.varint(int64(report.ExecType))
+	w.string(report.ExecRefID)
+	return w.end()
+}
+
+// DecodeReport decodes a frame into the report, as [DecodeQuote].
+func DecodeReport(b []byte, report *Report) (int, error) {
+	r, n, err := newCodecReader(b, MessageReport)
+	if err != nil {
+		return 0, err
+	}
+	report.OrderID = r.string()
+	report.Symbol = r.string()
+	report.Side = Side(r.varint())
+	report.SecondaryOrderID = r.string()
+	report.ClOrdID = r.string()
+	report.OrdStatus = OrdStatus(r.varint())
+	report.Account = r.string()
+	report.TimeInForce = TimeInForce(r.varint())
+	report.LastQty = r.decimal()
+	report.LastPx = r.decimal()
+	report.TransactTime = r.time()
+	report.ExecInst = r.string()
+	report.ExecID = r.string()
+	report.ExecType = ExecType(r.varint())
+	report.ExecRefID = r.string()
+	return n, r.done()
+}
+
+// AppendPositionMemo appends the memo as a frame to the buffer, as
+// [AppendQuote].
+func AppendPositionMemo(b []byte, memo *PositionMemo) ([]byte, error) {
+	w := codecWriter{b: b}
+	w.begin(MessagePositionMemo)
+	w.string(memo.Symbol)
+	w.decimal(memo.Quantity)
+	w.decimal(memo.AvgPx)
+	w.decimal(memo.Realised)
+	return w.end()
+}
+
+// DecodePositionMemo decodes a frame into the memo, as [DecodeQuote].
+func DecodePositionMemo(b []byte, memo *PositionMemo) (int, error) {
+	r, n, err := newCodecReader(b, MessagePositionMemo)
+	if err != nil {
+		return 0, err
+	}
+	memo.Symbol = r.string()
+	memo.Quantity = r.decimal()
+	memo.AvgPx = r.decimal()
+	memo.Realised = r.decimal()
+	return n, r.done()
+}
+
+// PeekMessageType returns the type of the frame at the start of the buffer,
+// so the caller can choose the decode function.
+func PeekMessageType(b []byte) (MessageType, error) {
+	_, body, err := frame(b)
+	if err != nil {
+		return 0, err
+	}
+	return MessageType(body[1]), nil
+}
+
+// ReadFrame reads one whole frame from the reader into the buffer, growing
+// it if necessary, and returns the frame. It returns [io.EOF] only if there
+// is no frame at all, and [ErrFrameTooLarge] without reading the frame if its
+// length exceeds [MaxFrameSize].
+func ReadFrame(r *bufio.Reader, buf []byte) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > MaxFrameSize {
+		return nil, ErrFrameTooLarge
+	}
+	header := binary.AppendUvarint(buf[:0], size)
+	total := len(header) + int(size)
+	if cap(buf) < total {
+		buf = make([]byte, total)
+		copy(buf, header)
+	}
+	buf = buf[:total]
+	if _, err := io.ReadFull(r, buf[len(header):]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf, nil
+}
+
+// frame returns the total length of the frame at the start of the buffer and
+// its body, after checking the version is the one that can be decoded.
+func frame(b []byte) (int, []byte, error) {
+	size, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, ErrShortFrame
+	}
+	if size > MaxFrameSize {
+		return 0, nil, ErrFrameTooLarge
+	}
+	if size < 2 || size > uint64(len(b)-n) {
+		return 0, nil, ErrShortFrame
+	}
+	total := n + int(size)
+	body := b[n:total]
+	if body[0] != CodecVersion {
+		return 0, nil, fmt.Errorf("mkt: unsupported codec version %d", body[0])
+	}
+	return total, body, nil
+}
+
+type codecWriter struct {
+	b     []byte
+	start int
+	err   error
+}
+
+// begin reserves the most space that the length prefix could take, so the
+// body need not be copied unless the prefix is shorter.
+func (x *codecWriter) begin(t MessageType) {
+	x.start = len(x.b)
+	x.b = append(x.b, 0, 0, 0, 0, 0, CodecVersion, byte(t))
+}
+
+func (x *codecWriter) end() ([]byte, error) {
+	if x.err != nil {
+		return x.b[:x.start], x.err
+	}
+	const reserved = 5
+	body := len(x.b) - x.start - reserved
+	if body > MaxFrameSize {
+		return x.b[:x.start], ErrFrameTooLarge
+	}
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(body))
+	copy(x.b[x.start+reserved-n:], prefix[:n])
+	if n < reserved {
+		x.b = append(x.b[:x.start], x.b[x.start+reserved-n:]...)
+	}
+	return x.b, nil
+}
+
+func (x *codecWriter) varint(v int64) {
+	x.b = binary.AppendVarint(x.b, v)
+}
+
+func (x *codecWriter) string(s string) {
+	x.b = binary.AppendUvarint(x.b, uint64(len(s)))
+	x.b = append(x.b, s...)
+}
+
+func (x *codecWriter) decimal(d decimal.Decimal) {
+	coefficient := d.Coefficient()
+	if !coefficient.IsInt64() {
+		x.err = fmt.Errorf("mkt: %s is too large to encode", d)
+		return
+	}
+	x.varint(int64(d.Exponent()))
+	x.varint(coefficient.Int64())
+}
+
+func (x *codecWriter) time(t time.Time) {
+	if t.IsZero() {
+		x.varint(0)
+		return
+	}
+	x.varint(t.UnixNano())
+}
+
+type codecReader struct {
+	b   []byte
+	err error
+}
+
+func newCodecReader(b []byte, t MessageType) (codecReader, int, error) {
+	n, body, err := frame(b)
+	if err != nil {
+		return codecReader{}, 0, err
+	}
+	if MessageType(body[1]) != t {
+		return codecReader{}, 0, fmt.Errorf("mkt: message type %d is not %d", body[1], t)
+	}
+	return codecReader{b: body[2:]}, n, nil
+}
+
+func (x *codecReader) done() error {
+	if x.err == nil && len(x.b) > 0 {
+		return errors.New("mkt: trailing bytes in frame")
+	}
+	return x.err
+}
+
+func (x *codecReader) varint() int64 {
+	if x.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(x.b)
+	if n <= 0 {
+		x.err = ErrShortFrame
+		return 0
+	}
+	x.b = x.b[n:]
+	return v
+}
+
+func (x *codecReader) string() string {
+	if x.err != nil {
+		return ""
+	}
+	size, n := binary.Uvarint(x.b)
+	if n <= 0 || uint64(len(x.b)-n) < size {
+		x.err = ErrShortFrame
+		return ""
+	}
+	s := string(x.b[n : n+int(size)])
+	x.b = x.b[n+int(size):]
+	return s
+}
+
+func (x *codecReader) decimal() decimal.Decimal {
+	exp := x.varint()
+	coefficient := x.varint()
+	if x.err != nil {
+		return decimal.Zero
+	}
+	return decimal.New(coefficient, int32(exp))
+}
+
+func (x *codecReader) time() time.Time {
+	nanos := x.varint()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
@@ -0,0 +1,209 @@
+package mkt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+
+	quote := &Quote{Symbol: "A", BidPx: decimal.New(4215, -2), BidSize: decimal.New(100, 0), AskPx: decimal.New(4216, -2), AskSize: decimal.New(-3, 5)}
+	trade := &Trade{Symbol: "A", LastQty: decimal.New(10, 0), LastPx: decimal.New(4216, -2)}
+	report := &Report{
+		OrderID:      "O1",
+		Symbol:       "A",
+		Side:         Sell,
+		OrdStatus:    OrdStatusPartiallyFilled,
+		TimeInForce:  IOC,
+		LastQty:      decimal.New(10, 0),
+		LastPx:       decimal.New(4216, -2),
+		TransactTime: time.Date(2026, 10, 19, 9, 30, 0, 123, time.UTC),
+		ExecInst:     "e",
+		ExecID:       "E2",
+		ExecType:     ExecTypeTradeCorrect,
+		ExecRefID:    "E1",
+	}
+	memo := &PositionMemo{Symbol: "A", Quantity: decimal.New(-10, 0), AvgPx: decimal.New(42155, -3), Realised: decimal.New(-125, -1)}
+
+	var b []byte
+	var err error
+	b, err = AppendQuote(b, quote)
+	assert.Nil(t, err)
+	b, err = AppendTrade(b, trade)
+	assert.Nil(t, err)
+	b, err = AppendReport(b, report)
+	assert.Nil(t, err)
+	b, err = AppendPositionMemo(b, memo)
+	assert.Nil(t, err)
+
+	messageType, err := PeekMessageType(b)
+	assert.Nil(t, err)
+	assert.Equal(t, MessageQuote, messageType)
+
+	var decodedQuote Quote
+	n, err := DecodeQuote(b, &decodedQuote)
+	assert.Nil(t, err)
+	assert.Equal(t, quote.Symbol, decodedQuote.Symbol)
+	assert.True(t, quote.BidPx.Equal(decodedQuote.BidPx))
+	assert.True(t, quote.AskSize.Equal(decodedQuote.AskSize))
+	b = b[n:]
+
+	var decodedTrade Trade
+	_, err = DecodeQuote(b, &decodedQuote)
+	assert.NotNil(t, err, "wrong type")
+	n, err = DecodeTrade(b, &decodedTrade)
+	assert.Nil(t, err)
+	assert.True(t, trade.LastPx.Equal(decodedTrade.LastPx))
+	assert.True(t, decodedTrade.AvgPx.IsZero())
+	b = b[n:]
+
+	var decodedReport Report
+	n, err = DecodeReport(b, &decodedReport)
+	assert.Nil(t, err)
+	assert.Equal(t, report.OrderID, decodedReport.OrderID)
+	assert.Equal(t, report.Side, decodedReport.Side)
+	assert.Equal(t, report.OrdStatus, decodedReport.OrdStatus)
+	assert.Equal(t, report.TimeInForce, decodedReport.TimeInForce)
+	assert.True(t, report.TransactTime.Equal(decodedReport.TransactTime))
+	assert.Equal(t, "e", decodedReport.ExecInst)
+	assert.Equal(t, "E2", decodedReport.ExecID)
+	assert.Equal(t, ExecTypeTradeCorrect, decodedReport.ExecType)
+	assert.Equal(t, "E1", decodedReport.ExecRefID)
+	b = b[n:]
+
+	var decodedMemo PositionMemo
+	n, err = DecodePositionMemo(b, &decodedMemo)
+	assert.Nil(t, err)
+	assert.True(t, memo.Realised.Equal(decodedMemo.Realised))
+	assert.Equal(t, len(b), n)
+
+	b, _ = AppendReport(nil, &Report{OrderID: "O2"})
+	_, err = DecodeReport(b, &decodedReport)
+	assert.Nil(t, err)
+	assert.True(t, decodedReport.TransactTime.IsZero())
+
+}
+
+func TestCodecErrors(t *testing.T) {
+
+	b, err := AppendQuote([]byte("prefix"), &Quote{Symbol: "A", BidPx: decimal.RequireFromString("123456789012345678901234567890")})
+	assert.NotNil(t, err)
+	assert.Equal(t, "prefix", string(b), "buffer unchanged")
+
+	b, err = AppendQuote(nil, &Quote{Symbol: strings.Repeat("A", 200)})
+	assert.Nil(t, err)
+	var quote Quote
+	_, err = DecodeQuote(b, &quote)
+	assert.Nil(t, err, "two byte length prefix")
+	assert.Equal(t, 200, len(quote.Symbol))
+
+	_, err = DecodeQuote(b[:len(b)-1], &quote)
+	assert.ErrorIs(t, err, ErrShortFrame)
+	_, err = DecodeQuote(nil, &quote)
+	assert.ErrorIs(t, err, ErrShortFrame)
+
+	b, _ = AppendQuote(nil, &Quote{Symbol: "A"})
+	b[1] = CodecVersion + 1
+	_, err = DecodeQuote(b, &quote)
+	assert.ErrorContains(t, err, "version")
+
+	//
+	// A length prefix that would overflow an int, or is merely too large.
+	//
+	huge := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, CodecVersion, byte(MessageQuote)}
+	_, err = DecodeQuote(huge, &quote)
+	assert.ErrorIs(t, err, ErrFrameTooLarge)
+	_, err = PeekMessageType(huge)
+	assert.ErrorIs(t, err, ErrFrameTooLarge)
+	large := binary.AppendUvarint(nil, MaxFrameSize+1)
+	_, err = PeekMessageType(large)
+	assert.ErrorIs(t, err, ErrFrameTooLarge)
+	short := binary.AppendUvarint(nil, 100)
+	_, err = PeekMessageType(append(short, CodecVersion, byte(MessageQuote)))
+	assert.ErrorIs(t, err, ErrShortFrame)
+
+	//
+	// A frame that could not be read back is not written.
+	//
+	b, err = AppendReport([]byte("prefix"), &Report{OrderID: strings.Repeat("A", MaxFrameSize)})
+	assert.ErrorIs(t, err, ErrFrameTooLarge)
+	assert.Equal(t, "prefix", string(b), "buffer unchanged")
+	b, err = AppendReport(nil, &Report{OrderID: strings.Repeat("A", MaxFrameSize-100)})
+	assert.Nil(t, err)
+	_, err = ReadFrame(bufio.NewReader(bytes.NewReader(b)), nil)
+	assert.Nil(t, err)
+
+}
+
+func TestReadFrame(t *testing.T) {
+
+	var stream bytes.Buffer
+	for _, symbol := range []string{"A", strings.Repeat("B", 300)} {
+		b, err := AppendTrade(nil, &Trade{Symbol: symbol, LastQty: DecimalOne, LastPx: DecimalOne})
+		assert.Nil(t, err)
+		stream.Write(b)
+	}
+
+	r := bufio.NewReader(&stream)
+	buf := make([]byte, 0, 64)
+
+	var trade Trade
+	for _, expected := range []int{1, 300} {
+		frame, err := ReadFrame(r, buf)
+		assert.Nil(t, err)
+		_, err = DecodeTrade(frame, &trade)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, len(trade.Symbol))
+	}
+
+	_, err := ReadFrame(r, buf)
+	assert.True(t, errors.Is(err, io.EOF))
+
+	b, _ := AppendTrade(nil, &Trade{Symbol: "A"})
+	_, err = ReadFrame(bufio.NewReader(bytes.NewReader(b[:len(b)-1])), buf)
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+
+	huge := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}
+	_, err = ReadFrame(bufio.NewReader(bytes.NewReader(huge)), buf)
+	assert.ErrorIs(t, err, ErrFrameTooLarge)
+
+}
+
+func FuzzDecode(f *testing.F) {
+
+	quote, _ := AppendQuote(nil, &Quote{Symbol: "A", BidPx: DecimalOne})
+	report, _ := AppendReport(nil, &Report{OrderID: "O1", ExecID: "E1"})
+	f.Add(quote)
+	f.Add(report)
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		_, _ = PeekMessageType(b)
+		_, _ = DecodeQuote(b, &Quote{})
+		_, _ = DecodeTrade(b, &Trade{})
+		_, _ = DecodeReport(b, &Report{})
+		_, _ = DecodePositionMemo(b, &PositionMemo{})
+		_, _ = ReadFrame(bufio.NewReader(bytes.NewReader(b)), nil)
+	})
+
+}
+
+func BenchmarkAppendDecodeQuote(b *testing.B) {
+	quote := &Quote{Symbol: "A", BidPx: decimal.New(4215, -2), BidSize: decimal.New(100, 0), AskPx: decimal.New(4216, -2), AskSize: decimal.New(100, 0)}
+	buf := make([]byte, 0, 64)
+	var decoded Quote
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ = AppendQuote(buf[:0], quote)
+		_, _ = DecodeQuote(buf, &decoded)
+	}
+}
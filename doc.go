@@ -1,2 +1,17 @@
 // Package mkt defines some market concepts.
+//
+// # Pooling
+//
+// The data objects published on channels, [Quote], [Trade], [PositionMemo]
+// and [Report], each have a zero function and a pool constructor so a steady
+// state pipeline need not allocate them. The ownership rules are the same for
+// every type:
+//
+//   - whoever takes an item from a pool owns it until it is published;
+//   - publishing an item on a channel passes ownership to the receiver, so
+//     the sender must not read or write the item afterwards;
+//   - the final owner recycles the item, after which nobody may use it.
+//
+// An item not recycled is simply garbage collected, so recycling is an
+// optimisation rather than an obligation.
 package mkt
@@ -0,0 +1,440 @@
+package mkt
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Engine is an in-process matching engine for a single [Listing], intended
+// for testing strategies without a live counterparty.
+//
+// Orders are matched in price-time priority. A [*Ticket] with a zero price is
+// a market order and never rests in the book; neither does any remainder of an
+// IOC order. Every other order is treated as GTC.
+//
+// The engine is deterministic: given the same sequence of tickets and the
+// same clock it produces the same reports, trades and quotes in the same
+// order. It is not safe for concurrent use.
+type Engine[T AnyListing] struct {
+	listing T
+	bids    []*engineOrder          // Best price first, then arrival.
+	asks    []*engineOrder          // Best price first, then arrival.
+	live    map[string]*engineOrder // Resting orders by OrderID.
+	arrival int64                   // Arrival sequence.
+	count   int64                   // Source of SecondaryOrderID values.
+	clock   func() time.Time        //
+	quote   Quote                   // The last published quote.
+	reports chan *Report            // Optional channel.
+	trades  chan *Trade             // Optional channel.
+	quotes  chan *Quote             // Optional channel.
+}
+
+type engineOrder struct {
+	ticket           Ticket
+	secondaryOrderID string
+	cumQty           decimal.Decimal
+	leavesQty        decimal.Decimal
+	arrival          int64
+}
+
+// EngineOption is any option that can be applied when constructing the engine.
+type EngineOption[T AnyListing] func(*Engine[T])
+
+// WithEngineClock sets the source of TransactTime for reports. The default is
+// [time.Now]; tests should supply a fixed clock.
+func WithEngineClock[T AnyListing](clock func() time.Time) EngineOption[T] {
+	return func(engine *Engine[T]) {
+		engine.clock = clock
+	}
+}
+
+// WithEngineReports writes a [*Report] to the channel for every change to an
+// order.
+func WithEngineReports[T AnyListing](c chan *Report) EngineOption[T] {
+	return func(engine *Engine[T]) {
+		engine.reports = c
+	}
+}
+
+// WithEngineTrades writes a [*Trade] to the channel for every match.
+func WithEngineTrades[T AnyListing](c chan *Trade) EngineOption[T] {
+	return func(engine *Engine[T]) {
+		engine.trades = c
+	}
+}
+
+// WithEngineQuotes writes a [*Quote] to the channel whenever the best bid or
+// ask changes.
+func WithEngineQuotes[T AnyListing](c chan *Quote) EngineOption[T] {
+	return func(engine *Engine[T]) {
+		engine.quotes = c
+	}
+}
+
+// NewEngine returns an empty [*Engine] for the listing.
+func NewEngine[T AnyListing](listing T, options ...EngineOption[T]) *Engine[T] {
+	engine := &Engine[T]{
+		listing: listing,
+		live:    map[string]*engineOrder{},
+		clock:   time.Now,
+	}
+	engine.quote.Symbol = listing.Definition().Symbol
+	for _, option := range options {
+		option(engine)
+	}
+	return engine
+}
+
+// Quote returns the current best bid and ask.
+func (x *Engine[T]) Quote() *Quote {
+	quote := x.top()
+	return &quote
+}
+
+// Submit a new order, cancel or replace according to [Ticket.MsgType]. If the
+// request is rejected this function returns an error. A rejected new order is
+// also reported with [OrdStatusRejected]. A rejected cancel or replace leaves
+// the existing order unchanged and is reported with [ExecTypeRejected] and
+// the status of the order, or [OrdStatusRejected] if there is no such order.
+func (x *Engine[T]) Submit(ticket *Ticket) error {
+
+	if ticket == nil {
+		return errors.New("mkt.Engine: nil ticket")
+	}
+
+	var err error
+	switch ticket.MsgType {
+	case OrderNew:
+		err = x.new(ticket)
+	case OrderCancel:
+		err = x.cancel(ticket)
+	case OrderReplace:
+		err = x.replace(ticket)
+	default:
+		err = fmt.Errorf("mkt.Engine: unsupported MsgType %d", ticket.MsgType)
+	}
+	if err != nil && (ticket.MsgType == OrderCancel || ticket.MsgType == OrderReplace) {
+		x.refuse(ticket)
+	}
+
+	x.publishQuote()
+	return err
+
+}
+
+func (x *Engine[T]) new(ticket *Ticket) error {
+
+	if _, ok := x.live[ticket.OrderID]; ok {
+		x.reject(ticket)
+		return fmt.Errorf("mkt.Engine: duplicate OrderID %s", ticket.OrderID)
+	}
+	if err := x.validate(ticket); err != nil {
+		x.reject(ticket)
+		return err
+	}
+
+	x.count++
+	order := &engineOrder{
+		ticket:           *ticket,
+		secondaryOrderID: strconv.FormatInt(x.count, 10),
+		leavesQty:        ticket.OrderQty,
+	}
+	x.report(order, OrdStatusNew, decimal.Zero, decimal.Zero)
+
+	x.work(order)
+	return nil
+
+}
+
+func (x *Engine[T]) cancel(ticket *Ticket) error {
+
+	order, ok := x.live[ticket.OrderID]
+	if !ok {
+		return fmt.Errorf("mkt.Engine: unknown OrderID %s", ticket.OrderID)
+	}
+
+	x.remove(order)
+	x.report(order, OrdStatusCanceled, decimal.Zero, decimal.Zero)
+	return nil
+
+}
+
+func (x *Engine[T]) replace(ticket *Ticket) error {
+
+	order, ok := x.live[ticket.OrderID]
+	if !ok {
+		return fmt.Errorf("mkt.Engine: unknown OrderID %s", ticket.OrderID)
+	}
+	if ticket.Side != order.ticket.Side {
+		return errors.New("mkt.Engine: cannot replace the side")
+	}
+	if ticket.Price.IsZero() {
+		return errors.New("mkt.Engine: cannot replace with a market order")
+	}
+	if err := x.validate(ticket); err != nil {
+		return err
+	}
+	leavesQty := ticket.OrderQty.Sub(order.cumQty)
+	if !leavesQty.IsPositive() {
+		return fmt.Errorf("mkt.Engine: OrderQty %s is not above CumQty %s", ticket.OrderQty, order.cumQty)
+	}
+
+	//
+	// Priority is kept only when the price is unchanged and the quantity is
+	// not increased. An order replaced as IOC no longer rests, so is worked
+	// again and any remainder canceled.
+	//
+	keep := ticket.Price.Equal(order.ticket.Price) && !leavesQty.GreaterThan(order.leavesQty) &&
+		ticket.TimeInForce != IOC
+
+	x.remove(order)
+	order.ticket.OrderQty = ticket.OrderQty
+	order.ticket.Price = ticket.Price
+	order.ticket.TimeInForce = ticket.TimeInForce
+	order.leavesQty = leavesQty
+
+	status := OrdStatusNew
+	if order.cumQty.IsPositive() {
+		status = OrdStatusPartiallyFilled
+	}
+	x.report(order, status, decimal.Zero, decimal.Zero)
+
+	if keep {
+		x.rest(order)
+		return nil
+	}
+	order.arrival = 0
+	x.work(order)
+	return nil
+
+}
+
+// validate the terms of the ticket against the listing.
+func (x *Engine[T]) validate(ticket *Ticket) error {
+
+	def := x.listing.Definition()
+
+	if ticket.Symbol != def.Symbol {
+		return fmt.Errorf("mkt.Engine: symbol %s is not %s", ticket.Symbol, def.Symbol)
+	}
+	if ticket.Side != Buy && ticket.Side != Sell {
+		return errors.New("mkt.Engine: invalid side")
+	}
+	if !ticket.OrderQty.IsPositive() || !Units(ticket.OrderQty, def.RoundLot, def.MinTradeVol).Equal(ticket.OrderQty) {
+		return fmt.Errorf("mkt.Engine: OrderQty %s is not a valid quantity", ticket.OrderQty)
+	}
+	if ticket.Price.IsNegative() {
+		return fmt.Errorf("mkt.Engine: Price %s is negative", ticket.Price)
+	}
+	if !ticket.Price.IsZero() && !def.RoundPrice(ticket.Price).Equal(ticket.Price) {
+		return fmt.Errorf("mkt.Engine: Price %s is not a whole tick", ticket.Price)
+	}
+	return nil
+
+}
+
+// work matches the order against the opposite side of the book, then either
+// rests or cancels any remainder.
+func (x *Engine[T]) work(order *engineOrder) {
+
+	side := &x.asks
+	if order.ticket.Side == Sell {
+		side = &x.bids
+	}
+
+	for order.leavesQty.IsPositive() && len(*side) > 0 {
+
+		best := (*side)[0]
+		if !order.ticket.Price.IsZero() && !order.ticket.Side.Within(best.ticket.Price, order.ticket.Price) {
+			break
+		}
+
+		lastQty := decimal.Min(order.leavesQty, best.leavesQty)
+		lastPx := best.ticket.Price
+
+		x.fill(order, lastQty, lastPx)
+		x.fill(best, lastQty, lastPx)
+		if !best.leavesQty.IsPositive() {
+			*side = (*side)[1:]
+			delete(x.live, best.ticket.OrderID)
+		}
+
+		if x.trades != nil {
+			x.trades <- &Trade{Symbol: x.quote.Symbol, LastQty: lastQty, LastPx: lastPx}
+		}
+
+	}
+
+	if !order.leavesQty.IsPositive() {
+		return
+	}
+	if order.ticket.Price.IsZero() || order.ticket.TimeInForce == IOC {
+		x.report(order, OrdStatusCanceled, decimal.Zero, decimal.Zero)
+		return
+	}
+
+	x.arrival++
+	order.arrival = x.arrival
+	x.rest(order)
+
+}
+
+func (x *Engine[T]) fill(order *engineOrder, lastQty, lastPx decimal.Decimal) {
+	order.cumQty = order.cumQty.Add(lastQty)
+	order.leavesQty = order.leavesQty.Sub(lastQty)
+	status := OrdStatusPartiallyFilled
+	if !order.leavesQty.IsPositive() {
+		status = OrdStatusFilled
+	}
+	x.report(order, status, lastQty, lastPx)
+}
+
+// rest inserts the order into the book behind all orders at the same or a
+// better price.
+func (x *Engine[T]) rest(order *engineOrder) {
+
+	side := &x.bids
+	if order.ticket.Side == Sell {
+		side = &x.asks
+	}
+	book := *side
+
+	price := order.ticket.Price
+	i := sort.Search(len(book), func(i int) bool {
+		other := book[i].ticket.Price
+		if order.ticket.Side == Buy {
+			return other.LessThan(price) || (other.Equal(price) && book[i].arrival > order.arrival)
+		}
+		return other.GreaterThan(price) || (other.Equal(price) && book[i].arrival > order.arrival)
+	})
+
+	book = append(book, nil)
+	copy(book[i+1:], book[i:])
+	book[i] = order
+	*side = book
+
+	x.live[order.ticket.OrderID] = order
+
+}
+
+func (x *Engine[T]) remove(order *engineOrder) {
+
+	side := &x.bids
+	if order.ticket.Side == Sell {
+		side = &x.asks
+	}
+	for i, other := range *side {
+		if other == order {
+			*side = append((*side)[:i], (*side)[i+1:]...)
+			break
+		}
+	}
+	delete(x.live, order.ticket.OrderID)
+
+}
+
+func (x *Engine[T]) report(order *engineOrder, status OrdStatus, lastQty, lastPx decimal.Decimal) {
+	if x.reports == nil {
+		return
+	}
+	x.reports <- &Report{
+		OrderID:          order.ticket.OrderID,
+		Symbol:           order.ticket.Symbol,
+		Side:             order.ticket.Side,
+		SecondaryOrderID: order.secondaryOrderID,
+		OrdStatus:        status,
+		TimeInForce:      order.ticket.TimeInForce,
+		LastQty:          lastQty,
+		LastPx:           lastPx,
+		TransactTime:     x.clock(),
+	}
+}
+
+func (x *Engine[T]) reject(ticket *Ticket) {
+	if x.reports == nil {
+		return
+	}
+	x.reports <- &Report{
+		OrderID:      ticket.OrderID,
+		Symbol:       ticket.Symbol,
+		Side:         ticket.Side,
+		OrdStatus:    OrdStatusRejected,
+		TimeInForce:  ticket.TimeInForce,
+		TransactTime: x.clock(),
+	}
+}
+
+// refuse reports a rejected cancel or replace.
+func (x *Engine[T]) refuse(ticket *Ticket) {
+	if x.reports == nil {
+		return
+	}
+	order, ok := x.live[ticket.OrderID]
+	if !ok {
+		x.reports <- &Report{
+			OrderID:      ticket.OrderID,
+			Symbol:       ticket.Symbol,
+			Side:         ticket.Side,
+			ExecType:     ExecTypeRejected,
+			OrdStatus:    OrdStatusRejected,
+			TimeInForce:  ticket.TimeInForce,
+			TransactTime: x.clock(),
+		}
+		return
+	}
+	status := OrdStatusNew
+	if order.cumQty.IsPositive() {
+		status = OrdStatusPartiallyFilled
+	}
+	x.reports <- &Report{
+		OrderID:          order.ticket.OrderID,
+		Symbol:           order.ticket.Symbol,
+		Side:             order.ticket.Side,
+		SecondaryOrderID: order.secondaryOrderID,
+		ExecType:         ExecTypeRejected,
+		OrdStatus:        status,
+		TimeInForce:      order.ticket.TimeInForce,
+		TransactTime:     x.clock(),
+	}
+}
+
+// top returns the best bid and ask, with the total size at each price.
+func (x *Engine[T]) top() Quote {
+	quote := Quote{Symbol: x.quote.Symbol}
+	quote.BidPx, quote.BidSize = level(x.bids)
+	quote.AskPx, quote.AskSize = level(x.asks)
+	return quote
+}
+
+func (x *Engine[T]) publishQuote() {
+	quote := x.top()
+	if quote.BidPx.Equal(x.quote.BidPx) && quote.BidSize.Equal(x.quote.BidSize) &&
+		quote.AskPx.Equal(x.quote.AskPx) && quote.AskSize.Equal(x.quote.AskSize) {
+		return
+	}
+	x.quote = quote
+	if x.quotes != nil {
+		published := quote
+		x.quotes <- &published
+	}
+}
+
+func level(book []*engineOrder) (price, size decimal.Decimal) {
+	if len(book) == 0 {
+		return decimal.Zero, decimal.Zero
+	}
+	price = book[0].ticket.Price
+	size = decimal.Zero
+	for _, order := range book {
+		if !order.ticket.Price.Equal(price) {
+			break
+		}
+		size = size.Add(order.leavesQty)
+	}
+	return
+}
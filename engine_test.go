@@ -0,0 +1,208 @@
+package mkt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEngine() (*Engine[*Listing], chan *Report, chan *Trade, chan *Quote) {
+
+	listing := &Listing{
+		Symbol:        "A",
+		TickIncrement: decimal.New(5, -1),
+		RoundLot:      decimal.New(10, 0),
+		MinTradeVol:   decimal.New(10, 0),
+	}
+
+	reports := make(chan *Report, 64)
+	trades := make(chan *Trade, 64)
+	quotes := make(chan *Quote, 64)
+
+	now := time.Date(2024, 8, 20, 8, 0, 0, 0, time.UTC)
+
+	engine := NewEngine(
+		listing,
+		WithEngineClock[*Listing](func() time.Time { return now }),
+		WithEngineReports[*Listing](reports),
+		WithEngineTrades[*Listing](trades),
+		WithEngineQuotes[*Listing](quotes),
+	)
+	return engine, reports, trades, quotes
+}
+
+func newTicket(orderID string, side Side, qty, price int64, tif TimeInForce) *Ticket {
+	return &Ticket{
+		Order:       Order{MsgType: OrderNew, OrderID: orderID, Side: side, Symbol: "A"},
+		OrderQty:    decimal.New(qty, 0),
+		Price:       decimal.New(price, 0),
+		TimeInForce: tif,
+	}
+}
+
+func drainReports(c chan *Report) []*Report {
+	var reports []*Report
+	for len(c) > 0 {
+		reports = append(reports, <-c)
+	}
+	return reports
+}
+
+func TestEngineRejects(t *testing.T) {
+
+	engine, reports, _, _ := newTestEngine()
+
+	ticket := newTicket("1", Buy, 15, 42, GTC)
+	assert.NotNil(t, engine.Submit(ticket), "odd lot")
+
+	ticket = newTicket("2", Buy, 10, 42, GTC)
+	ticket.Price = decimal.New(4225, -2)
+	assert.NotNil(t, engine.Submit(ticket), "not a whole tick")
+
+	ticket = newTicket("3", Buy, 10, 42, GTC)
+	ticket.Symbol = "B"
+	assert.NotNil(t, engine.Submit(ticket), "wrong symbol")
+
+	for _, report := range drainReports(reports) {
+		assert.Equal(t, OrdStatusRejected, report.OrdStatus)
+	}
+
+	ticket = newTicket("4", Buy, 10, 42, GTC)
+	ticket.MsgType = OrderCancel
+	assert.NotNil(t, engine.Submit(ticket), "unknown order")
+	report := <-reports
+	assert.Equal(t, ExecTypeRejected, report.ExecType)
+	assert.Equal(t, OrdStatusRejected, report.OrdStatus)
+
+	//
+	// A refused replace leaves the order as it was.
+	//
+	assert.Nil(t, engine.Submit(newTicket("5", Buy, 10, 42, GTC)))
+	drainReports(reports)
+	ticket = newTicket("5", Buy, 15, 42, GTC)
+	ticket.MsgType = OrderReplace
+	assert.NotNil(t, engine.Submit(ticket), "odd lot")
+	report = <-reports
+	assert.Equal(t, ExecTypeRejected, report.ExecType)
+	assert.Equal(t, OrdStatusNew, report.OrdStatus)
+	assert.Equal(t, 0, len(reports))
+	assert.True(t, engine.Quote().BidSize.Equal(decimal.New(10, 0)))
+
+}
+
+func TestEnginePriceTimePriority(t *testing.T) {
+
+	engine, reports, trades, quotes := newTestEngine()
+
+	assert.Nil(t, engine.Submit(newTicket("S1", Sell, 10, 43, GTC)))
+	assert.Nil(t, engine.Submit(newTicket("S2", Sell, 10, 42, GTC)))
+	assert.Nil(t, engine.Submit(newTicket("S3", Sell, 10, 42, GTC)))
+	drainReports(reports)
+
+	quote := engine.Quote()
+	assert.True(t, quote.AskPx.Equal(decimal.New(42, 0)))
+	assert.True(t, quote.AskSize.Equal(decimal.New(20, 0)))
+	assert.Equal(t, 3, len(quotes))
+
+	assert.Nil(t, engine.Submit(newTicket("B1", Buy, 20, 43, GTC)))
+
+	got := drainReports(reports)
+	assert.Equal(t, 5, len(got))
+	assert.Equal(t, "B1", got[0].OrderID)
+	assert.Equal(t, OrdStatusNew, got[0].OrdStatus)
+	assert.Equal(t, OrdStatusPartiallyFilled, got[1].OrdStatus)
+	assert.Equal(t, "S2", got[2].OrderID, "earliest at best price")
+	assert.Equal(t, OrdStatusFilled, got[2].OrdStatus)
+	assert.Equal(t, OrdStatusFilled, got[3].OrdStatus)
+	assert.Equal(t, "S3", got[4].OrderID)
+
+	assert.Equal(t, 2, len(trades))
+	trade := <-trades
+	assert.True(t, trade.LastPx.Equal(decimal.New(42, 0)), "resting price")
+
+	quote = engine.Quote()
+	assert.True(t, quote.AskPx.Equal(decimal.New(43, 0)))
+
+}
+
+func TestEngineMarketAndIOC(t *testing.T) {
+
+	engine, reports, _, _ := newTestEngine()
+
+	assert.Nil(t, engine.Submit(newTicket("S1", Sell, 10, 42, GTC)))
+	drainReports(reports)
+
+	assert.Nil(t, engine.Submit(newTicket("B1", Buy, 20, 0, GTC)))
+	got := drainReports(reports)
+	assert.Equal(t, OrdStatusCanceled, got[len(got)-1].OrdStatus, "market remainder")
+
+	assert.Nil(t, engine.Submit(newTicket("B2", Buy, 10, 42, IOC)))
+	got = drainReports(reports)
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, OrdStatusCanceled, got[1].OrdStatus, "IOC never rests")
+	assert.True(t, engine.Quote().BidPx.IsZero())
+
+}
+
+func TestEngineCancelReplace(t *testing.T) {
+
+	engine, reports, _, _ := newTestEngine()
+
+	assert.Nil(t, engine.Submit(newTicket("B1", Buy, 10, 41, GTC)))
+	assert.Nil(t, engine.Submit(newTicket("B2", Buy, 10, 41, GTC)))
+	drainReports(reports)
+
+	//
+	// Increasing the quantity loses priority.
+	//
+	replace := newTicket("B1", Buy, 20, 41, GTC)
+	replace.MsgType = OrderReplace
+	assert.Nil(t, engine.Submit(replace))
+	assert.Equal(t, "B2", engine.bids[0].ticket.OrderID)
+
+	assert.Nil(t, engine.Submit(newTicket("S1", Sell, 10, 41, GTC)))
+	got := drainReports(reports)
+	assert.Equal(t, "B2", got[len(got)-1].OrderID)
+
+	cancel := newTicket("B1", Buy, 20, 41, GTC)
+	cancel.MsgType = OrderCancel
+	assert.Nil(t, engine.Submit(cancel))
+	got = drainReports(reports)
+	assert.Equal(t, 1, len(got))
+	assert.Equal(t, OrdStatusCanceled, got[0].OrdStatus)
+	assert.True(t, engine.Quote().BidPx.IsZero())
+
+	//
+	// Replaced as IOC an order no longer rests, even at the same price.
+	//
+	assert.Nil(t, engine.Submit(newTicket("B3", Buy, 20, 41, GTC)))
+	drainReports(reports)
+	replace = newTicket("B3", Buy, 20, 41, IOC)
+	replace.MsgType = OrderReplace
+	assert.Nil(t, engine.Submit(replace))
+	got = drainReports(reports)
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, OrdStatusCanceled, got[1].OrdStatus)
+	assert.True(t, engine.Quote().BidPx.IsZero())
+
+}
+
+func TestEngineDeterministic(t *testing.T) {
+
+	run := func() []Report {
+		engine, reports, _, _ := newTestEngine()
+		engine.Submit(newTicket("S1", Sell, 30, 43, GTC))
+		engine.Submit(newTicket("S2", Sell, 10, 42, GTC))
+		engine.Submit(newTicket("B1", Buy, 50, 43, IOC))
+		var result []Report
+		for _, report := range drainReports(reports) {
+			result = append(result, *report)
+		}
+		return result
+	}
+
+	assert.Equal(t, run(), run())
+
+}
@@ -0,0 +1,172 @@
+package mkt
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+)
+
+// The ExecType is the reason for an execution report, FIX field 150.
+type ExecType int64
+
+// Recognised ExecType values, a subset from FIX 4.4. The zero value is
+// reserved for representing 'no value'.
+const (
+	ExecTypeNew ExecType = iota + 1
+	ExecTypeTrade
+	ExecTypeTradeCancel
+	ExecTypeTradeCorrect
+	ExecTypeCanceled
+	ExecTypeReplaced
+	ExecTypeRejected
+	ExecTypeExpired
+	ExecTypePendingNew
+	ExecTypePendingCancel
+	ExecTypePendingReplace
+)
+
+var (
+	execTypeToString map[ExecType]string
+	stringToExecType map[string]ExecType
+	longToExecType   map[string]ExecType
+)
+
+func init() {
+	execTypeToString = map[ExecType]string{
+		ExecTypeNew:            "NEW",
+		ExecTypeTrade:          "TRADE",
+		ExecTypeTradeCancel:    "BUST",
+		ExecTypeTradeCorrect:   "CORR",
+		ExecTypeCanceled:       "CXLD",
+		ExecTypeReplaced:       "RPLD",
+		ExecTypeRejected:       "REJD",
+		ExecTypeExpired:        "EXPD",
+		ExecTypePendingNew:     "PNEW",
+		ExecTypePendingCancel:  "PCXL",
+		ExecTypePendingReplace: "PRPL",
+	}
+	stringToExecType = map[string]ExecType{}
+	for x, s := range execTypeToString {
+		stringToExecType[s] = x
+	}
+	longToExecType = map[string]ExecType{
+		"NEW":             ExecTypeNew,
+		"TRADE":           ExecTypeTrade,
+		"TRADE_CANCEL":    ExecTypeTradeCancel,
+		"TRADE_CORRECT":   ExecTypeTradeCorrect,
+		"CANCELED":        ExecTypeCanceled,
+		"REPLACED":        ExecTypeReplaced,
+		"REJECTED":        ExecTypeRejected,
+		"EXPIRED":         ExecTypeExpired,
+		"PENDING_NEW":     ExecTypePendingNew,
+		"PENDING_CANCEL":  ExecTypePendingCancel,
+		"PENDING_REPLACE": ExecTypePendingReplace,
+	}
+}
+
+// String returns a mnemonic of the [ExecType].
+func (x ExecType) String() string {
+	return execTypeToString[x]
+}
+
+// ExecTypeFromString returns a recognised [ExecType] or zero.
+func ExecTypeFromString(s string) ExecType {
+	return stringToExecType[s]
+}
+
+// ParseExecType returns the [ExecType] for the mnemonic, the long name or the
+// FIX code. Unlike [ExecTypeFromString] it returns an error for any other
+// value.
+func ParseExecType(s string) (ExecType, error) {
+	if x, ok := stringToExecType[s]; ok {
+		return x, nil
+	}
+	if x, ok := longToExecType[s]; ok {
+		return x, nil
+	}
+	if x := ExecTypeFromFIX(field.NewExecType(enum.ExecType(s))); x != 0 {
+		return x, nil
+	}
+	return 0, fmt.Errorf("mkt.ParseExecType: unknown value %q", s)
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (x ExecType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.String())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (x *ExecType) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*x = ExecTypeFromString(s)
+	return nil
+}
+
+// AsQuickFIX returns the [ExecType] as a QuickFIX field. If the value is not
+// one of those recognised, this function returns a valid value that will
+// likely be rejected by the counterparty, rather than panicking.
+func (x ExecType) AsQuickFIX() field.ExecTypeField {
+	switch x {
+	case ExecTypeNew:
+		return field.NewExecType(enum.ExecType_NEW)
+	case ExecTypeTrade:
+		return field.NewExecType(enum.ExecType_TRADE)
+	case ExecTypeTradeCancel:
+		return field.NewExecType(enum.ExecType_TRADE_CANCEL)
+	case ExecTypeTradeCorrect:
+		return field.NewExecType(enum.ExecType_TRADE_CORRECT)
+	case ExecTypeCanceled:
+		return field.NewExecType(enum.ExecType_CANCELED)
+	case ExecTypeReplaced:
+		return field.NewExecType(enum.ExecType_REPLACED)
+	case ExecTypeRejected:
+		return field.NewExecType(enum.ExecType_REJECTED)
+	case ExecTypeExpired:
+		return field.NewExecType(enum.ExecType_EXPIRED)
+	case ExecTypePendingNew:
+		return field.NewExecType(enum.ExecType_PENDING_NEW)
+	case ExecTypePendingCancel:
+		return field.NewExecType(enum.ExecType_PENDING_CANCEL)
+	case ExecTypePendingReplace:
+		return field.NewExecType(enum.ExecType_PENDING_REPLACE)
+	default:
+		return field.NewExecType(enum.ExecType_ORDER_STATUS)
+	}
+}
+
+// ExecTypeFromFIX returns the equivalent [ExecType] from the QuickFIX field,
+// or zero if there is no equivalence. The FIX 4.2 partial fill and fill values
+// are both [ExecTypeTrade].
+func ExecTypeFromFIX(execType field.ExecTypeField) ExecType {
+	switch execType.Value() {
+	case enum.ExecType_NEW:
+		return ExecTypeNew
+	case enum.ExecType_TRADE, enum.ExecType_PARTIAL_FILL, enum.ExecType_FILL:
+		return ExecTypeTrade
+	case enum.ExecType_TRADE_CANCEL:
+		return ExecTypeTradeCancel
+	case enum.ExecType_TRADE_CORRECT:
+		return ExecTypeTradeCorrect
+	case enum.ExecType_CANCELED:
+		return ExecTypeCanceled
+	case enum.ExecType_REPLACED:
+		return ExecTypeReplaced
+	case enum.ExecType_REJECTED:
+		return ExecTypeRejected
+	case enum.ExecType_EXPIRED:
+		return ExecTypeExpired
+	case enum.ExecType_PENDING_NEW:
+		return ExecTypePendingNew
+	case enum.ExecType_PENDING_CANCEL:
+		return ExecTypePendingCancel
+	case enum.ExecType_PENDING_REPLACE:
+		return ExecTypePendingReplace
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,492 @@
+package mkt
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/shopspring/decimal"
+)
+
+// Delimiters between the fields of a FIX message in tag=value form. FIX uses
+// SOH on the wire, whereas logs often use a pipe for readability.
+const (
+	SOH  byte = 0x01
+	Pipe byte = '|'
+)
+
+// DefaultBeginString is used when writing a [FIXMessage] that has no
+// BeginString.
+const DefaultBeginString = "FIX.4.4"
+
+// Errors for a FIX message that fails validation.
+var (
+	ErrFIXBodyLength = errors.New("mkt: FIX BodyLength mismatch")
+	ErrFIXCheckSum   = errors.New("mkt: FIX CheckSum mismatch")
+)
+
+// FIXField is one tag=value pair.
+type FIXField struct {
+	Tag   int
+	Value string
+}
+
+// FIXMessage is a FIX message in tag=value form, parsed or written without a
+// QuickFIX session. The fields are in message order, excluding BeginString,
+// BodyLength and CheckSum which are computed when writing and validated when
+// parsing. Data fields that may contain the delimiter are not supported.
+type FIXMessage struct {
+	BeginString string
+	Fields      []FIXField
+}
+
+// Tags used by the mappings to and from [Order], [Ticket] and [Report].
+const (
+	fixTagAccount          = 1
+	fixTagBeginString      = 8
+	fixTagBodyLength       = 9
+	fixTagCheckSum         = 10
+	fixTagClOrdID          = 11
+	fixTagExecID           = 17
+	fixTagExecInst         = 18
+	fixTagExecRefID        = 19
+	fixTagLastPx           = 31
+	fixTagLastQty          = 32
+	fixTagMsgType          = 35
+	fixTagOrderID          = 37
+	fixTagOrderQty         = 38
+	fixTagOrdStatus        = 39
+	fixTagPrice            = 44
+	fixTagSide             = 54
+	fixTagSymbol           = 55
+	fixTagTimeInForce      = 59
+	fixTagTransactTime     = 60
+	fixTagExecType         = 150
+	fixTagSecondaryOrderID = 198
+)
+
+// fixTimeFormat is the UTCTimestamp format written, with milliseconds.
+const fixTimeFormat = "20060102-15:04:05.000"
+
+// Get returns the value of the first field with the tag.
+func (x *FIXMessage) Get(tag int) (string, bool) {
+	for _, f := range x.Fields {
+		if f.Tag == tag {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set replaces the value of the first field with the tag, or appends the
+// field if there is none.
+func (x *FIXMessage) Set(tag int, value string) {
+	for i := range x.Fields {
+		if x.Fields[i].Tag == tag {
+			x.Fields[i].Value = value
+			return
+		}
+	}
+	x.Fields = append(x.Fields, FIXField{Tag: tag, Value: value})
+}
+
+// MsgType returns the value of the MsgType field, FIX field 35.
+func (x *FIXMessage) MsgType() string {
+	s, _ := x.Get(fixTagMsgType)
+	return s
+}
+
+// Append writes the message to the buffer, which may be nil, with the
+// delimiter between fields. BeginString is written first, then BodyLength,
+// then MsgType and the remaining fields in order, and finally CheckSum.
+//
+// The CheckSum is always that of the message delimited by SOH, so that a
+// message written with a [Pipe] is still valid once the pipes are replaced.
+func (x *FIXMessage) Append(b []byte, delim byte) []byte {
+
+	beginString := x.BeginString
+	if beginString == "" {
+		beginString = DefaultBeginString
+	}
+
+	//
+	// The body is written first so that its length is known.
+	//
+	var body []byte
+	if msgType, ok := x.Get(fixTagMsgType); ok {
+		body = appendFIXField(body, fixTagMsgType, msgType, delim)
+	}
+	for _, f := range x.Fields {
+		if f.Tag == fixTagMsgType {
+			continue
+		}
+		body = appendFIXField(body, f.Tag, f.Value, delim)
+	}
+
+	start := len(b)
+	b = appendFIXField(b, fixTagBeginString, beginString, delim)
+	b = appendFIXField(b, fixTagBodyLength, strconv.Itoa(len(body)), delim)
+	b = append(b, body...)
+	sum := fixCheckSum(b[start:], delim)
+	return appendFIXField(b, fixTagCheckSum, fmt.Sprintf("%03d", sum), delim)
+
+}
+
+// String returns the message delimited by [Pipe], for logging.
+func (x *FIXMessage) String() string {
+	return string(x.Append(nil, Pipe))
+}
+
+// ParseFIX parses one whole FIX message delimited by either [SOH] or [Pipe],
+// the delimiter being whichever follows the BeginString. It validates the
+// BodyLength and CheckSum, returning [ErrFIXBodyLength] or [ErrFIXCheckSum] if
+// they do not match. A trailing line ending is ignored.
+func ParseFIX(b []byte) (*FIXMessage, error) {
+
+	b = bytes.TrimRight(b, "\r\n")
+	if !bytes.HasPrefix(b, []byte("8=")) {
+		return nil, errors.New("mkt.ParseFIX: no BeginString")
+	}
+	end := bytes.IndexAny(b, "\x01|")
+	if end < 0 {
+		return nil, errors.New("mkt.ParseFIX: no delimiter")
+	}
+	delim := b[end]
+
+	fields, err := splitFIX(b, delim)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 3 || fields[1].Tag != fixTagBodyLength || fields[len(fields)-1].Tag != fixTagCheckSum {
+		return nil, errors.New("mkt.ParseFIX: missing BodyLength or CheckSum")
+	}
+
+	//
+	// BodyLength counts from the field after itself up to and including the
+	// delimiter before CheckSum.
+	//
+	trailer := bytes.LastIndex(b, []byte{delim, '1', '0', '='}) + 1
+	bodyStart := len("8=") + len(fields[0].Value) + 1 + len("9=") + len(fields[1].Value) + 1
+	length, err := strconv.Atoi(fields[1].Value)
+	if err != nil || length != trailer-bodyStart {
+		return nil, ErrFIXBodyLength
+	}
+	sum, err := strconv.Atoi(fields[len(fields)-1].Value)
+	if err != nil || len(fields[len(fields)-1].Value) != 3 || sum != fixCheckSum(b[:trailer], delim) {
+		return nil, ErrFIXCheckSum
+	}
+
+	return &FIXMessage{
+		BeginString: fields[0].Value,
+		Fields:      fields[2 : len(fields)-1],
+	}, nil
+
+}
+
+// ScanFIX is a [bufio.SplitFunc] that returns each FIX message in the input,
+// so that a log file or drop copy can be read with a [bufio.Scanner] and each
+// token given to [ParseFIX]. Anything between messages, such as timestamps and
+// line endings, is skipped.
+func ScanFIX(data []byte, atEOF bool) (int, []byte, error) {
+
+	start := bytes.Index(data, []byte("8=FIX"))
+	if start < 0 {
+		if atEOF {
+			return len(data), nil, nil
+		}
+		//
+		// Keep enough to match a BeginString split across reads.
+		//
+		return max(0, len(data)-len("8=FIX")+1), nil, nil
+	}
+
+	end := bytes.IndexAny(data[start:], "\x01|")
+	if end < 0 {
+		return fixNeedMore(data, start, atEOF)
+	}
+	delim := data[start+end]
+
+	trailer := bytes.Index(data[start:], []byte{delim, '1', '0', '='})
+	if trailer < 0 {
+		return fixNeedMore(data, start, atEOF)
+	}
+
+	//
+	// The CheckSum is always three digits, so the message ends there even
+	// when a log line has no final delimiter.
+	//
+	end = start + trailer + len("|10=000")
+	if end > len(data) {
+		return fixNeedMore(data, start, atEOF)
+	}
+	token := data[start:end]
+	if end < len(data) && data[end] == delim {
+		end++
+	}
+	return end, token, nil
+
+}
+
+// fixNeedMore requests more data for an incomplete message, or discards it at
+// the end of the input.
+func fixNeedMore(data []byte, start int, atEOF bool) (int, []byte, error) {
+	if atEOF {
+		return len(data), nil, nil
+	}
+	return start, nil, nil
+}
+
+var _ bufio.SplitFunc = ScanFIX
+
+// ReadFIXReports returns the [*Report] in every ExecutionReport in the input,
+// such as a drop copy log, ignoring other messages. It returns an error for
+// the first message that is invalid.
+func ReadFIXReports(r io.Reader) ([]*Report, error) {
+	var reports []*Report
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	scanner.Split(ScanFIX)
+	for scanner.Scan() {
+		msg, err := ParseFIX(scanner.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		if enum.MsgType(msg.MsgType()) != enum.MsgType_EXECUTION_REPORT {
+			continue
+		}
+		report, err := ReportFromFIX(msg)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, scanner.Err()
+}
+
+// splitFIX splits the message into its fields. A final delimiter is optional.
+func splitFIX(b []byte, delim byte) ([]FIXField, error) {
+	var fields []FIXField
+	for len(b) > 0 {
+		next := bytes.IndexByte(b, delim)
+		if next < 0 {
+			next = len(b)
+		}
+		tag, value, ok := bytes.Cut(b[:next], []byte{'='})
+		if !ok {
+			return nil, fmt.Errorf("mkt.ParseFIX: malformed field %q", b[:next])
+		}
+		n, err := strconv.Atoi(string(tag))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("mkt.ParseFIX: malformed tag %q", tag)
+		}
+		fields = append(fields, FIXField{Tag: n, Value: string(value)})
+		if next == len(b) {
+			break
+		}
+		b = b[next+1:]
+	}
+	return fields, nil
+}
+
+func appendFIXField(b []byte, tag int, value string, delim byte) []byte {
+	b = strconv.AppendInt(b, int64(tag), 10)
+	b = append(b, '=')
+	b = append(b, value...)
+	return append(b, delim)
+}
+
+// fixCheckSum returns the sum of the bytes modulo 256, counting each
+// delimiter as SOH.
+func fixCheckSum(b []byte, delim byte) int {
+	sum := 0
+	for _, c := range b {
+		if c == delim {
+			c = SOH
+		}
+		sum += int(c)
+	}
+	return sum % 256
+}
+
+// AsFIX returns the order as a FIX message, using the FIX fields documented on
+// [Order].
+func (x *Order) AsFIX() *FIXMessage {
+	msg := &FIXMessage{}
+	msg.Set(fixTagMsgType, string(x.MsgType.AsQuickFIX().Value()))
+	msg.Set(fixTagOrderID, x.OrderID)
+	msg.Set(fixTagSide, string(x.Side.AsQuickFIX().Value()))
+	msg.Set(fixTagSymbol, x.Symbol)
+	return msg
+}
+
+// AsFIX returns the ticket as a FIX message, as [Order.AsFIX] together with
+// the quantity, any price and any time in force.
+func (x *Ticket) AsFIX() *FIXMessage {
+	msg := x.Order.AsFIX()
+	msg.Set(fixTagOrderQty, x.OrderQty.String())
+	if !x.Price.IsZero() {
+		msg.Set(fixTagPrice, x.Price.String())
+	}
+	if x.TimeInForce != 0 {
+		msg.Set(fixTagTimeInForce, string(x.TimeInForce.AsQuickFIX().Value()))
+	}
+	return msg
+}
+
+// AsFIX returns the report as a FIX ExecutionReport, using the FIX fields
+// documented on [Report]. Empty fields are omitted.
+func (x *Report) AsFIX() *FIXMessage {
+	msg := &FIXMessage{}
+	msg.Set(fixTagMsgType, string(enum.MsgType_EXECUTION_REPORT))
+	msg.Set(fixTagOrderID, x.OrderID)
+	setFIXString(msg, fixTagSymbol, x.Symbol)
+	if x.Side != 0 {
+		msg.Set(fixTagSide, string(x.Side.AsQuickFIX().Value()))
+	}
+	setFIXString(msg, fixTagSecondaryOrderID, x.SecondaryOrderID)
+	setFIXString(msg, fixTagClOrdID, x.ClOrdID)
+	if x.OrdStatus != 0 {
+		msg.Set(fixTagOrdStatus, string(x.OrdStatus.AsQuickFIX().Value()))
+	}
+	setFIXString(msg, fixTagAccount, x.Account)
+	if x.TimeInForce != 0 {
+		msg.Set(fixTagTimeInForce, string(x.TimeInForce.AsQuickFIX().Value()))
+	}
+	msg.Set(fixTagLastQty, x.LastQty.String())
+	msg.Set(fixTagLastPx, x.LastPx.String())
+	if !x.TransactTime.IsZero() {
+		msg.Set(fixTagTransactTime, x.TransactTime.UTC().Format(fixTimeFormat))
+	}
+	setFIXString(msg, fixTagExecInst, x.ExecInst)
+	setFIXString(msg, fixTagExecID, x.ExecID)
+	if x.ExecType != 0 {
+		msg.Set(fixTagExecType, string(x.ExecType.AsQuickFIX().Value()))
+	}
+	setFIXString(msg, fixTagExecRefID, x.ExecRefID)
+	return msg
+}
+
+func setFIXString(msg *FIXMessage, tag int, value string) {
+	if value != "" {
+		msg.Set(tag, value)
+	}
+}
+
+// OrderFromFIX returns the [*Order] in a NewOrderSingle, OrderCancelRequest
+// or OrderCancelReplaceRequest.
+func OrderFromFIX(msg *FIXMessage) (*Order, error) {
+	order := &Order{}
+	switch enum.MsgType(msg.MsgType()) {
+	case enum.MsgType_ORDER_SINGLE:
+		order.MsgType = OrderNew
+	case enum.MsgType_ORDER_CANCEL_REQUEST:
+		order.MsgType = OrderCancel
+	case enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST:
+		order.MsgType = OrderReplace
+	default:
+		return nil, fmt.Errorf("mkt.OrderFromFIX: unsupported MsgType %q", msg.MsgType())
+	}
+	order.OrderID, _ = msg.Get(fixTagOrderID)
+	order.Symbol, _ = msg.Get(fixTagSymbol)
+	if s, ok := msg.Get(fixTagSide); ok {
+		order.Side = SideFromFIX(field.NewSide(enum.Side(s)))
+	}
+	return order, nil
+}
+
+// TicketFromFIX returns the [*Ticket] in a message, as [OrderFromFIX].
+func TicketFromFIX(msg *FIXMessage) (*Ticket, error) {
+	order, err := OrderFromFIX(msg)
+	if err != nil {
+		return nil, err
+	}
+	ticket := &Ticket{Order: *order}
+	if ticket.OrderQty, err = fixDecimal(msg, fixTagOrderQty); err != nil {
+		return nil, err
+	}
+	if ticket.Price, err = fixDecimal(msg, fixTagPrice); err != nil {
+		return nil, err
+	}
+	if s, ok := msg.Get(fixTagTimeInForce); ok {
+		ticket.TimeInForce = TimeInForceFromFIX(field.NewTimeInForce(enum.TimeInForce(s)))
+	}
+	return ticket, nil
+}
+
+// ReportFromFIX returns the [*Report] in an ExecutionReport.
+func ReportFromFIX(msg *FIXMessage) (*Report, error) {
+
+	if enum.MsgType(msg.MsgType()) != enum.MsgType_EXECUTION_REPORT {
+		return nil, errors.New("mkt.ReportFromFIX: not an ExecutionReport")
+	}
+
+	report := &Report{}
+	var ok bool
+	if report.OrderID, ok = msg.Get(fixTagOrderID); !ok {
+		return nil, errors.New("mkt.ReportFromFIX: no OrderID")
+	}
+	report.Symbol, _ = msg.Get(fixTagSymbol)
+	report.SecondaryOrderID, _ = msg.Get(fixTagSecondaryOrderID)
+	report.ClOrdID, _ = msg.Get(fixTagClOrdID)
+	report.Account, _ = msg.Get(fixTagAccount)
+	report.ExecInst, _ = msg.Get(fixTagExecInst)
+	report.ExecID, _ = msg.Get(fixTagExecID)
+	report.ExecRefID, _ = msg.Get(fixTagExecRefID)
+
+	if s, ok := msg.Get(fixTagSide); ok {
+		report.Side = SideFromFIX(field.NewSide(enum.Side(s)))
+	}
+	if s, ok := msg.Get(fixTagOrdStatus); ok {
+		report.OrdStatus = OrdStatusFromFIX(field.NewOrdStatus(enum.OrdStatus(s)))
+	}
+	if s, ok := msg.Get(fixTagTimeInForce); ok {
+		report.TimeInForce = TimeInForceFromFIX(field.NewTimeInForce(enum.TimeInForce(s)))
+	}
+	if s, ok := msg.Get(fixTagExecType); ok {
+		report.ExecType = ExecTypeFromFIX(field.NewExecType(enum.ExecType(s)))
+	}
+
+	var err error
+	if report.LastQty, err = fixDecimal(msg, fixTagLastQty); err != nil {
+		return nil, err
+	}
+	if report.LastPx, err = fixDecimal(msg, fixTagLastPx); err != nil {
+		return nil, err
+	}
+	if s, ok := msg.Get(fixTagTransactTime); ok {
+		if report.TransactTime, err = parseFIXTime(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+
+}
+
+// fixDecimal returns the decimal value of the field, or zero if it is absent.
+func fixDecimal(msg *FIXMessage, tag int) (decimal.Decimal, error) {
+	s, ok := msg.Get(tag)
+	if !ok {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("mkt: FIX field %d: %w", tag, err)
+	}
+	return d, nil
+}
+
+// parseFIXTime parses a UTCTimestamp with or without fractional seconds.
+func parseFIXTime(s string) (time.Time, error) {
+	t, err := time.Parse("20060102-15:04:05.999999999", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("mkt: FIX field %d: %w", fixTagTransactTime, err)
+	}
+	return t, nil
+}
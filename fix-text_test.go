@@ -0,0 +1,208 @@
+package mkt
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// A logon from the FIX specification, with its published BodyLength and
+// CheckSum.
+const testLogon = "8=FIX.4.2|9=65|35=A|49=SERVER|56=CLIENT|34=177|52=20090107-18:15:16|98=0|108=30|10=062|"
+
+func TestParseFIX(t *testing.T) {
+
+	msg, err := ParseFIX([]byte(testLogon))
+	assert.Nil(t, err)
+	assert.Equal(t, "FIX.4.2", msg.BeginString)
+	assert.Equal(t, "A", msg.MsgType())
+	v, ok := msg.Get(108)
+	assert.True(t, ok)
+	assert.Equal(t, "30", v)
+	assert.Equal(t, 7, len(msg.Fields))
+
+	//
+	// The same message delimited by SOH, without a final delimiter but with
+	// a line ending.
+	//
+	soh := strings.TrimSuffix(strings.ReplaceAll(testLogon, "|", "\x01"), "\x01") + "\n"
+	msg, err = ParseFIX([]byte(soh))
+	assert.Nil(t, err)
+	assert.Equal(t, "A", msg.MsgType())
+
+	_, err = ParseFIX([]byte(strings.Replace(testLogon, "9=65", "9=64", 1)))
+	assert.ErrorIs(t, err, ErrFIXBodyLength)
+	_, err = ParseFIX([]byte(strings.Replace(testLogon, "10=062", "10=063", 1)))
+	assert.ErrorIs(t, err, ErrFIXCheckSum)
+	_, err = ParseFIX([]byte(strings.Replace(testLogon, "98=0", "98=1", 1)))
+	assert.ErrorIs(t, err, ErrFIXCheckSum)
+	_, err = ParseFIX([]byte("35=A|10=000|"))
+	assert.NotNil(t, err)
+	_, err = ParseFIX([]byte("8=FIX.4.2|9=5|35=A|10=000|"))
+	assert.NotNil(t, err)
+	_, err = ParseFIX([]byte("8=FIX.4.2|9=5|x=A|10=000|"))
+	assert.NotNil(t, err)
+
+}
+
+func TestFIXMessageAppend(t *testing.T) {
+
+	msg, err := ParseFIX([]byte(testLogon))
+	assert.Nil(t, err)
+	assert.Equal(t, testLogon, msg.String())
+	assert.Equal(t, strings.ReplaceAll(testLogon, "|", "\x01"), string(msg.Append(nil, SOH)))
+
+	//
+	// MsgType is always written first, and the BeginString defaulted.
+	//
+	msg = &FIXMessage{}
+	msg.Set(55, "A")
+	msg.Set(35, "D")
+	msg.Set(55, "B")
+	b := msg.Append([]byte("prefix"), Pipe)
+	assert.True(t, bytes.HasPrefix(b, []byte("prefix8=FIX.4.4|9=")))
+	parsed, err := ParseFIX(b[len("prefix"):])
+	assert.Nil(t, err)
+	assert.Equal(t, []FIXField{{35, "D"}, {55, "B"}}, parsed.Fields)
+
+}
+
+func TestTicketFIX(t *testing.T) {
+
+	ticket := &Ticket{
+		Order:       Order{MsgType: OrderNew, OrderID: "1", Side: Sell, Symbol: "A"},
+		OrderQty:    decimal.New(100, 0),
+		Price:       decimal.New(4250, -2),
+		TimeInForce: IOC,
+	}
+	msg, err := ParseFIX(ticket.AsFIX().Append(nil, SOH))
+	assert.Nil(t, err)
+	assert.Equal(t, "D", msg.MsgType())
+
+	got, err := TicketFromFIX(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, ticket.Order, got.Order)
+	assert.True(t, ticket.OrderQty.Equal(got.OrderQty))
+	assert.True(t, ticket.Price.Equal(got.Price))
+	assert.Equal(t, IOC, got.TimeInForce)
+
+	order, err := OrderFromFIX((&Order{MsgType: OrderCancel, OrderID: "1", Side: Buy, Symbol: "A"}).AsFIX())
+	assert.Nil(t, err)
+	assert.Equal(t, OrderCancel, order.MsgType)
+	assert.Equal(t, Buy, order.Side)
+
+	msg.Set(35, "8")
+	_, err = OrderFromFIX(msg)
+	assert.NotNil(t, err)
+	msg.Set(35, "D")
+	msg.Set(38, "lots")
+	_, err = TicketFromFIX(msg)
+	assert.NotNil(t, err)
+
+}
+
+func TestReportFIX(t *testing.T) {
+
+	report := &Report{
+		OrderID:          "1",
+		Symbol:           "A",
+		Side:             Buy,
+		SecondaryOrderID: "X1",
+		ClOrdID:          "C1",
+		OrdStatus:        OrdStatusPartiallyFilled,
+		Account:          "ACC",
+		TimeInForce:      GTC,
+		LastQty:          decimal.New(10, 0),
+		LastPx:           decimal.New(425, -1),
+		TransactTime:     time.Date(2024, 3, 1, 14, 30, 0, 123000000, time.UTC),
+		ExecInst:         "e",
+		ExecID:           "E2",
+		ExecType:         ExecTypeTradeCorrect,
+		ExecRefID:        "E1",
+	}
+	msg, err := ParseFIX([]byte(report.AsFIX().String()))
+	assert.Nil(t, err)
+	v, _ := msg.Get(60)
+	assert.Equal(t, "20240301-14:30:00.123", v)
+	v, _ = msg.Get(39)
+	assert.Equal(t, "1", v)
+	v, _ = msg.Get(150)
+	assert.Equal(t, "G", v)
+
+	got, err := ReportFromFIX(msg)
+	assert.Nil(t, err)
+	assert.True(t, report.LastQty.Equal(got.LastQty))
+	assert.True(t, report.LastPx.Equal(got.LastPx))
+	got.LastQty, got.LastPx = report.LastQty, report.LastPx
+	assert.Equal(t, report, got)
+
+	//
+	// Empty fields are omitted and absent fields are zero.
+	//
+	msg = (&Report{OrderID: "2"}).AsFIX()
+	_, ok := msg.Get(55)
+	assert.False(t, ok)
+	got, err = ReportFromFIX(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, OrdStatus(0), got.OrdStatus)
+	assert.True(t, got.TransactTime.IsZero())
+
+	msg.Set(60, "20240301-14:30:00")
+	got, err = ReportFromFIX(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC), got.TransactTime)
+	msg.Set(60, "yesterday")
+	_, err = ReportFromFIX(msg)
+	assert.NotNil(t, err)
+
+	_, err = ReportFromFIX(&FIXMessage{Fields: []FIXField{{35, "8"}}})
+	assert.NotNil(t, err)
+	_, err = ReportFromFIX(&FIXMessage{Fields: []FIXField{{35, "D"}, {37, "1"}}})
+	assert.NotNil(t, err)
+
+}
+
+func TestScanFIX(t *testing.T) {
+
+	report := (&Report{OrderID: "1", OrdStatus: OrdStatusNew}).AsFIX()
+	soh := string(report.Append(nil, SOH))
+	log := "09:00:00.000 IN " + testLogon + "\n" +
+		"09:00:00.001 OUT " + strings.TrimSuffix(testLogon, "|") + "\r\n" +
+		soh + soh +
+		"09:00:02.000 partial 8=FIX.4.4|9=5|35=0|"
+
+	scanner := bufio.NewScanner(strings.NewReader(log))
+	scanner.Buffer(make([]byte, 16), 1024)
+	scanner.Split(ScanFIX)
+
+	var types []string
+	for scanner.Scan() {
+		msg, err := ParseFIX(scanner.Bytes())
+		assert.Nil(t, err)
+		types = append(types, msg.MsgType())
+	}
+	assert.Nil(t, scanner.Err())
+	assert.Equal(t, []string{"A", "A", "8", "8"}, types)
+
+}
+
+func TestReadFIXReports(t *testing.T) {
+
+	fill := &Report{OrderID: "1", Symbol: "A", Side: Buy, LastQty: decimal.New(5, 0), LastPx: decimal.New(10, 0)}
+	log := testLogon + "\n" + fill.AsFIX().String() + "\n"
+
+	reports, err := ReadFIXReports(strings.NewReader(log))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(reports))
+	assert.Equal(t, "1", reports[0].OrderID)
+	assert.True(t, reports[0].LastQty.Equal(fill.LastQty))
+
+	_, err = ReadFIXReports(strings.NewReader(strings.Replace(log, "10=062", "10=063", 1)))
+	assert.ErrorIs(t, err, ErrFIXCheckSum)
+
+}
@@ -0,0 +1,621 @@
+package mkt
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/shopspring/decimal"
+)
+
+// Fixed is a fixed-point number: an int64 mantissa and a scale, the number of
+// decimal places, from 0 to 18. Fixed is a value type and its arithmetic
+// does not allocate, so it suits hot loops such as market data handling
+// where [decimal.Decimal] is too slow.
+//
+// The scale is usually taken per listing, from [Listing.PriceScale] and
+// [Listing.QtyScale]. Operands of different scales are aligned to the larger.
+// Arithmetic panics if the mantissa overflows, as does division by zero;
+// comparisons never overflow.
+type Fixed struct {
+	value int64
+	scale int32
+}
+
+var pow10 = [19]int64{
+	1, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9,
+	1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18,
+}
+
+// maxScale is the largest scale of a [Fixed].
+const maxScale = 18
+
+// NewFixed returns the [Fixed] number value * 10^-scale.
+func NewFixed(value int64, scale int32) Fixed {
+	return Fixed{value: value, scale: scale}
+}
+
+// FixedFromDecimal returns the decimal as a [Fixed] at the scale. It returns
+// an error if the conversion would lose precision or overflow.
+func FixedFromDecimal(d decimal.Decimal, scale int32) (Fixed, error) {
+	if scale < 0 || scale > maxScale {
+		return Fixed{}, fmt.Errorf("mkt.FixedFromDecimal: scale %d is out of range", scale)
+	}
+	shifted := d.Shift(scale)
+	if !shifted.IsInteger() {
+		return Fixed{}, fmt.Errorf("mkt.FixedFromDecimal: %s has more than %d decimals", d, scale)
+	}
+	if !shifted.BigInt().IsInt64() {
+		return Fixed{}, fmt.Errorf("mkt.FixedFromDecimal: %s overflows", d)
+	}
+	return Fixed{value: shifted.IntPart(), scale: scale}, nil
+}
+
+// Decimal returns the number as a [decimal.Decimal], without loss.
+func (x Fixed) Decimal() decimal.Decimal {
+	return decimal.New(x.value, -x.scale)
+}
+
+func (x Fixed) String() string {
+	return x.Decimal().StringFixed(x.scale)
+}
+
+// Value returns the mantissa.
+func (x Fixed) Value() int64 { return x.value }
+
+// Scale returns the number of decimal places.
+func (x Fixed) Scale() int32 { return x.scale }
+
+// Rescale returns the number at the scale, rounding half away from zero if
+// the scale is reduced.
+func (x Fixed) Rescale(scale int32) Fixed {
+	switch {
+	case scale == x.scale:
+		return x
+	case scale > x.scale:
+		return Fixed{value: mustScaleUp(x.value, scale-x.scale), scale: scale}
+	default:
+		return Fixed{value: divRound(x.value, pow10[x.scale-scale]), scale: scale}
+	}
+}
+
+// align returns the mantissas of both numbers at the larger scale.
+func align(x, y Fixed) (int64, int64, int32) {
+	switch {
+	case x.scale == y.scale:
+		return x.value, y.value, x.scale
+	case x.scale < y.scale:
+		return mustScaleUp(x.value, y.scale-x.scale), y.value, y.scale
+	default:
+		return x.value, mustScaleUp(y.value, x.scale-y.scale), x.scale
+	}
+}
+
+// Add returns x + y.
+func (x Fixed) Add(y Fixed) Fixed {
+	a, b, scale := align(x, y)
+	sum, ok := add64(a, b)
+	if !ok {
+		panic("mkt.Fixed: overflow")
+	}
+	return Fixed{value: sum, scale: scale}
+}
+
+// Sub returns x - y.
+func (x Fixed) Sub(y Fixed) Fixed {
+	a, b, scale := align(x, y)
+	if b == math.MinInt64 {
+		panic("mkt.Fixed: overflow")
+	}
+	difference, ok := add64(a, -b)
+	if !ok {
+		panic("mkt.Fixed: overflow")
+	}
+	return Fixed{value: difference, scale: scale}
+}
+
+// Mul returns x * y exactly, at the sum of the scales. If that sum is more
+// than 18 the product is rounded half away from zero to a scale of 18.
+func (x Fixed) Mul(y Fixed) Fixed {
+
+	negative := (x.value < 0) != (y.value < 0)
+	hi, lo := bits.Mul64(abs64(x.value), abs64(y.value))
+
+	scale := x.scale + y.scale
+	if scale > maxScale {
+		//
+		// The scale of each operand is at most 18, so the excess is too.
+		//
+		d := uint64(pow10[scale-maxScale])
+		if hi >= d {
+			panic("mkt.Fixed: overflow")
+		}
+		var r uint64
+		lo, r = bits.Div64(hi, lo, d)
+		hi = 0
+		if r >= d-r {
+			lo++
+		}
+		scale = maxScale
+	}
+	if hi != 0 || lo > math.MaxInt64 {
+		panic("mkt.Fixed: overflow")
+	}
+
+	value := int64(lo)
+	if negative {
+		value = -value
+	}
+	return Fixed{value: value, scale: scale}
+
+}
+
+// Div returns x / y at the scale, rounding half away from zero.
+func (x Fixed) Div(y Fixed, scale int32) Fixed {
+
+	if y.value == 0 {
+		panic("mkt.Fixed: division by zero")
+	}
+
+	negative := (x.value < 0) != (y.value < 0)
+	n, d := abs64(x.value), abs64(y.value)
+
+	//
+	// The quotient is n * 10^e / d, computed in 128 bits.
+	//
+	var q, r uint64
+	e := scale + y.scale - x.scale
+	if e >= 0 {
+		if e > maxScale {
+			panic("mkt.Fixed: scale out of range")
+		}
+		hi, lo := bits.Mul64(n, uint64(pow10[e]))
+		if hi >= d {
+			panic("mkt.Fixed: overflow")
+		}
+		q, r = bits.Div64(hi, lo, d)
+	} else {
+		if -e > maxScale {
+			panic("mkt.Fixed: scale out of range")
+		}
+		hi, lo := bits.Mul64(d, uint64(pow10[-e]))
+		if hi != 0 {
+			return Fixed{scale: scale}
+		}
+		d = lo
+		q, r = n/d, n%d
+	}
+	if q > math.MaxInt64 {
+		panic("mkt.Fixed: overflow")
+	}
+	if r >= d-r {
+		q++
+	}
+	if q > math.MaxInt64 {
+		panic("mkt.Fixed: overflow")
+	}
+
+	value := int64(q)
+	if negative {
+		value = -value
+	}
+	return Fixed{value: value, scale: scale}
+
+}
+
+// Mod returns x modulo y, with the sign of x.
+func (x Fixed) Mod(y Fixed) Fixed {
+	a, b, scale := align(x, y)
+	return Fixed{value: a % b, scale: scale}
+}
+
+// Neg returns -x.
+func (x Fixed) Neg() Fixed { return Fixed{value: -x.value, scale: x.scale} }
+
+// Abs returns the absolute value of x.
+func (x Fixed) Abs() Fixed {
+	if x.value < 0 {
+		return x.Neg()
+	}
+	return x
+}
+
+// Sign returns -1, 0 or 1.
+func (x Fixed) Sign() int {
+	switch {
+	case x.value < 0:
+		return -1
+	case x.value > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsZero returns true if x is zero.
+func (x Fixed) IsZero() bool { return x.value == 0 }
+
+// IsPositive returns true if x is greater than zero.
+func (x Fixed) IsPositive() bool { return x.value > 0 }
+
+// Cmp returns -1, 0 or 1 as x is less than, equal to or greater than y.
+func (x Fixed) Cmp(y Fixed) int {
+
+	if x.scale == y.scale {
+		return cmp64(x.value, y.value)
+	}
+	sign := x.Sign()
+	if sign != y.Sign() {
+		return cmp64(int64(sign), int64(y.Sign()))
+	}
+	if sign == 0 {
+		return 0
+	}
+
+	//
+	// Compare the magnitudes at the larger scale in 128 bits, so that the
+	// alignment cannot overflow.
+	//
+	a, b := abs64(x.value), abs64(y.value)
+	if x.scale < y.scale {
+		return sign * cmpScaled(a, y.scale-x.scale, b)
+	}
+	return -sign * cmpScaled(b, x.scale-y.scale, a)
+
+}
+
+// Equal returns true if x and y are the same number, whatever their scales.
+func (x Fixed) Equal(y Fixed) bool { return x.Cmp(y) == 0 }
+
+// LessThan returns true if x < y.
+func (x Fixed) LessThan(y Fixed) bool { return x.Cmp(y) < 0 }
+
+// GreaterThan returns true if x > y.
+func (x Fixed) GreaterThan(y Fixed) bool { return x.Cmp(y) > 0 }
+
+func abs64(x int64) uint64 {
+	if x < 0 {
+		return uint64(-x)
+	}
+	return uint64(x)
+}
+
+func cmp64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// cmpScaled compares a * 10^k with b.
+func cmpScaled(a uint64, k int32, b uint64) int {
+	hi, lo := bits.Mul64(a, uint64(pow10[k]))
+	switch {
+	case hi != 0 || lo > b:
+		return 1
+	case lo < b:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// scaleUp returns v * 10^k, or false if that overflows.
+func scaleUp(v int64, k int32) (int64, bool) {
+	p := pow10[k]
+	if v > math.MaxInt64/p || v < math.MinInt64/p {
+		return 0, false
+	}
+	return v * p, true
+}
+
+func mustScaleUp(v int64, k int32) int64 {
+	v, ok := scaleUp(v, k)
+	if !ok {
+		panic("mkt.Fixed: overflow")
+	}
+	return v
+}
+
+// add64 returns a + b, or false if that overflows.
+func add64(a, b int64) (int64, bool) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, false
+	}
+	return sum, true
+}
+
+// mul64 returns a * b, or false if that overflows.
+func mul64(a, b int64) (int64, bool) {
+	hi, lo := bits.Mul64(abs64(a), abs64(b))
+	if hi != 0 || lo > math.MaxInt64 {
+		return 0, false
+	}
+	if (a < 0) != (b < 0) {
+		return -int64(lo), true
+	}
+	return int64(lo), true
+}
+
+// divRound returns x / d rounded half away from zero, for positive d.
+func divRound(x, d int64) int64 {
+	q, r := x/d, x%d
+	if r < 0 {
+		r = -r
+	}
+	if r >= d-r {
+		if x < 0 {
+			return q - 1
+		}
+		return q + 1
+	}
+	return q
+}
+
+// FixedUnits is [Units] for [Fixed].
+func FixedUnits(x, unit, min Fixed) Fixed {
+	if x.Sign() <= 0 {
+		return Fixed{scale: x.scale}
+	}
+	if unit.IsZero() {
+		return x
+	}
+	u := x.Sub(x.Mod(unit))
+	if u.LessThan(min) {
+		return Fixed{scale: u.scale}
+	}
+	return u
+}
+
+// FixedCumQtyAvgPx is [CumQtyAvgPx] for [Fixed]. The average price is at
+// scale n. The weighted sum of the prices is exact, so that a notional
+// beyond an int64, such as in a listing with fine lots, does not overflow; it
+// panics only if the average price itself does.
+func FixedCumQtyAvgPx(cumQty, avgPx, lastQty, lastPx Fixed, n int32) (Fixed, Fixed) {
+	total := cumQty.Add(lastQty)
+	if v, ok := weightedSum(cumQty, avgPx, lastQty, lastPx); ok {
+		return total, v.Div(total, n)
+	}
+	return total, weightedAvgPx(cumQty, avgPx, lastQty, lastPx, total, n)
+}
+
+// weightedSum returns q1 * p1 + q2 * p2, or false if it cannot be held in a
+// [Fixed] without rounding or overflow.
+func weightedSum(q1, p1, q2, p2 Fixed) (Fixed, bool) {
+	s1, s2 := q1.scale+p1.scale, q2.scale+p2.scale
+	scale := max(s1, s2)
+	if scale > maxScale {
+		return Fixed{}, false
+	}
+	v1, ok := mul64(q1.value, p1.value)
+	if !ok {
+		return Fixed{}, false
+	}
+	v2, ok := mul64(q2.value, p2.value)
+	if !ok {
+		return Fixed{}, false
+	}
+	if v1, ok = scaleUp(v1, scale-s1); !ok {
+		return Fixed{}, false
+	}
+	if v2, ok = scaleUp(v2, scale-s2); !ok {
+		return Fixed{}, false
+	}
+	sum, ok := add64(v1, v2)
+	return Fixed{value: sum, scale: scale}, ok
+}
+
+// weightedAvgPx returns (q1 * p1 + q2 * p2) / total at scale n, rounding half
+// away from zero. It is the slow path of [FixedCumQtyAvgPx], for a weighted
+// sum that does not fit in a [Fixed].
+func weightedAvgPx(q1, p1, q2, p2, total Fixed, n int32) Fixed {
+
+	if total.value == 0 {
+		panic("mkt.Fixed: division by zero")
+	}
+
+	pow := func(k int32) *big.Int {
+		return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(k)), nil)
+	}
+	scale := max(q1.scale+p1.scale, q2.scale+p2.scale)
+	product := func(q, p Fixed) *big.Int {
+		v := new(big.Int).Mul(big.NewInt(q.value), big.NewInt(p.value))
+		return v.Mul(v, pow(scale-q.scale-p.scale))
+	}
+
+	//
+	// The average at scale n is sum * 10^(n + total.scale - scale) / total.
+	//
+	numerator := new(big.Int).Add(product(q1, p1), product(q2, p2))
+	denominator := big.NewInt(total.value)
+	if e := n + total.scale - scale; e >= 0 {
+		numerator.Mul(numerator, pow(e))
+	} else {
+		denominator.Mul(denominator, pow(-e))
+	}
+
+	q, r := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if r.Sign() != 0 && new(big.Int).Lsh(new(big.Int).Abs(r), 1).CmpAbs(denominator) >= 0 {
+		if numerator.Sign() == denominator.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	if !q.IsInt64() {
+		panic("mkt.Fixed: overflow")
+	}
+	return Fixed{value: q.Int64(), scale: n}
+
+}
+
+// PriceScale returns the scale of [Fixed] prices for the listing.
+func (x *Listing) PriceScale() int32 {
+	return x.PricePrecision()
+}
+
+// QtyScale returns the scale of [Fixed] quantities for the listing.
+func (x *Listing) QtyScale() int32 {
+	scale := Precision(x.RoundLot)
+	if p := Precision(x.MinTradeVol); p > scale {
+		scale = p
+	}
+	return scale
+}
+
+// FixedTrade is [Trade] for [Fixed].
+type FixedTrade struct {
+	Symbol      string
+	LastQty     Fixed
+	LastPx      Fixed
+	TradeVolume Fixed
+	AvgPx       Fixed
+}
+
+// NewFixedTrade returns the trade as a [FixedTrade] at the scales of the
+// listing, or an error if any field cannot be held without loss.
+func NewFixedTrade(trade *Trade, listing *Listing) (*FixedTrade, error) {
+	qtyScale, pxScale := listing.QtyScale(), listing.PriceScale()
+	x := &FixedTrade{Symbol: trade.Symbol}
+	for _, f := range []struct {
+		target *Fixed
+		source decimal.Decimal
+		scale  int32
+	}{
+		{&x.LastQty, trade.LastQty, qtyScale},
+		{&x.LastPx, trade.LastPx, pxScale},
+		{&x.TradeVolume, trade.TradeVolume, qtyScale},
+		{&x.AvgPx, trade.AvgPx, pxScale + 1},
+	} {
+		var err error
+		if *f.target, err = FixedFromDecimal(f.source, f.scale); err != nil {
+			return nil, err
+		}
+	}
+	return x, nil
+}
+
+// Trade returns this as a [*Trade].
+func (x *FixedTrade) Trade() *Trade {
+	return &Trade{
+		Symbol:      x.Symbol,
+		LastQty:     x.LastQty.Decimal(),
+		LastPx:      x.LastPx.Decimal(),
+		TradeVolume: x.TradeVolume.Decimal(),
+		AvgPx:       x.AvgPx.Decimal(),
+	}
+}
+
+// Aggregate is [Trade.Aggregate] for [FixedTrade].
+func (x *FixedTrade) Aggregate(trade *FixedTrade, precision int32) {
+
+	if x == nil || trade == nil {
+		return
+	}
+	if trade.Symbol != x.Symbol {
+		return
+	}
+
+	switch {
+	case x.TradeVolume.IsZero() && trade.TradeVolume.IsZero():
+		x.TradeVolume, x.AvgPx = FixedCumQtyAvgPx(x.LastQty, x.LastPx, trade.LastQty, trade.LastPx, precision)
+	case trade.TradeVolume.IsZero():
+		x.TradeVolume, x.AvgPx = FixedCumQtyAvgPx(x.TradeVolume, x.AvgPx, trade.LastQty, trade.LastPx, precision)
+	default:
+		x.TradeVolume, x.AvgPx = FixedCumQtyAvgPx(x.TradeVolume, x.AvgPx, trade.TradeVolume, trade.AvgPx, precision)
+	}
+
+	x.LastQty, x.LastPx = trade.LastQty, trade.LastPx
+
+}
+
+// FixedPosition is [Position] for [Fixed], for a single listing.
+type FixedPosition struct {
+	symbol     string
+	precision  int32
+	multiplier Fixed
+	quantity   Fixed
+	avgPx      Fixed
+	realised   Fixed
+}
+
+// NewFixedPosition returns a flat position in the listing. It returns an
+// error if the contract multiplier cannot be held as a [Fixed].
+func NewFixedPosition(listing *Listing) (*FixedPosition, error) {
+	multiplier := listing.ContractMultiplier
+	if multiplier.IsZero() {
+		multiplier = DecimalOne
+	}
+	m, err := FixedFromDecimal(multiplier, Precision(multiplier))
+	if err != nil {
+		return nil, err
+	}
+	return &FixedPosition{
+		symbol:     listing.Symbol,
+		precision:  listing.PriceScale() + 1,
+		multiplier: m,
+	}, nil
+}
+
+// Quantity returns the position, long is positive and short is negative.
+func (x *FixedPosition) Quantity() Fixed { return x.quantity }
+
+// AvgPx returns the average price of building the position.
+func (x *FixedPosition) AvgPx() Fixed { return x.avgPx }
+
+// Realised returns the realised profit/loss.
+func (x *FixedPosition) Realised() Fixed { return x.realised }
+
+// Memo returns a [*PositionMemo] for the current position.
+func (x *FixedPosition) Memo() *PositionMemo {
+	return &PositionMemo{
+		Symbol:   x.symbol,
+		Quantity: x.quantity.Decimal(),
+		AvgPx:    x.avgPx.Decimal(),
+		Realised: x.realised.Decimal(),
+	}
+}
+
+// Traded is [Position.Traded] for [FixedPosition].
+func (x *FixedPosition) Traded(side Side, lastQty, lastPx Fixed) {
+
+	if lastQty.IsZero() {
+		return
+	}
+	if side == Sell {
+		lastQty = lastQty.Neg()
+	}
+	if x.quantity.IsZero() {
+		x.quantity, x.avgPx = lastQty, lastPx
+		return
+	}
+	if x.quantity.Sign() == lastQty.Sign() {
+		x.quantity, x.avgPx = FixedCumQtyAvgPx(x.quantity, x.avgPx, lastQty, lastPx, x.precision)
+		return
+	}
+
+	profit := lastPx.Sub(x.avgPx)
+	if x.quantity.Sign() < 0 {
+		profit = profit.Neg()
+	}
+	profit = profit.Mul(x.multiplier)
+
+	switch {
+	case lastQty.Abs().Equal(x.quantity.Abs()):
+		x.realised = x.realised.Add(x.quantity.Abs().Mul(profit))
+		x.quantity, x.avgPx = Fixed{}, Fixed{}
+	case lastQty.Abs().GreaterThan(x.quantity.Abs()):
+		x.realised = x.realised.Add(x.quantity.Abs().Mul(profit))
+		x.quantity, x.avgPx = x.quantity.Add(lastQty), lastPx
+	default:
+		x.realised = x.realised.Add(lastQty.Abs().Mul(profit))
+		x.quantity = x.quantity.Add(lastQty)
+	}
+
+}
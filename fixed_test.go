@@ -0,0 +1,300 @@
+package mkt
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedDecimal(t *testing.T) {
+
+	f, err := FixedFromDecimal(decimal.RequireFromString("42.125"), 4)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(421250), f.Value())
+	assert.Equal(t, int32(4), f.Scale())
+	assert.True(t, f.Decimal().Equal(decimal.RequireFromString("42.125")))
+	assert.Equal(t, "42.1250", f.String())
+
+	_, err = FixedFromDecimal(decimal.RequireFromString("42.125"), 2)
+	assert.NotNil(t, err, "loses precision")
+	_, err = FixedFromDecimal(decimal.RequireFromString("1e20"), 0)
+	assert.NotNil(t, err, "overflow")
+	_, err = FixedFromDecimal(DecimalOne, 19)
+	assert.NotNil(t, err)
+
+	f, err = FixedFromDecimal(decimal.RequireFromString("-0.5"), 1)
+	assert.Nil(t, err)
+	assert.True(t, f.Decimal().Equal(decimal.RequireFromString("-0.5")))
+
+}
+
+func TestFixedArithmetic(t *testing.T) {
+
+	a := NewFixed(4215, 2) // 42.15
+	b := NewFixed(5, 1)    // 0.5
+
+	assert.True(t, a.Add(b).Equal(NewFixed(4265, 2)))
+	assert.Equal(t, int32(2), a.Add(b).Scale())
+	assert.True(t, a.Sub(b).Equal(NewFixed(4165, 2)))
+	assert.True(t, a.Mul(b).Equal(NewFixed(21075, 3)))
+	assert.True(t, a.Mod(b).Equal(NewFixed(15, 2)))
+	assert.True(t, a.Neg().Abs().Equal(a))
+	assert.Equal(t, -1, a.Neg().Sign())
+	assert.True(t, b.LessThan(a))
+	assert.True(t, a.GreaterThan(b))
+	assert.True(t, NewFixed(50, 2).Equal(b))
+
+	assert.True(t, a.Div(b, 2).Equal(NewFixed(843, 1)))
+	assert.True(t, NewFixed(2, 0).Div(NewFixed(3, 0), 4).Equal(NewFixed(6667, 4)))
+	assert.True(t, NewFixed(-2, 0).Div(NewFixed(3, 0), 4).Equal(NewFixed(-6667, 4)))
+	assert.True(t, NewFixed(1, 0).Div(NewFixed(8, 0), 2).Equal(NewFixed(13, 2)), "half away from zero")
+	assert.True(t, NewFixed(12345, 4).Div(NewFixed(1, 0), 2).Equal(NewFixed(123, 2)), "negative exponent")
+	assert.Panics(t, func() { a.Div(Fixed{}, 2) })
+
+	//
+	// The scale of a product is at most 18, and overflow panics.
+	//
+	product := NewFixed(25, 10).Mul(NewFixed(-2, 10))
+	assert.Equal(t, int32(18), product.Scale())
+	assert.True(t, product.Equal(NewFixed(-1, 18)), "half away from zero")
+	assert.True(t, product.Add(b).LessThan(b))
+	assert.Panics(t, func() { product.Add(a) }, "42.15 overflows at scale 18")
+	assert.Panics(t, func() { NewFixed(math.MaxInt64/2, 0).Mul(NewFixed(3, 0)) })
+	assert.Panics(t, func() { NewFixed(math.MaxInt64, 18).Mul(NewFixed(math.MaxInt64, 18)) })
+	assert.Panics(t, func() { NewFixed(math.MaxInt64, 0).Div(NewFixed(1, 1), 0) }, "quotient of 2^63 or more")
+	assert.True(t, NewFixed(math.MaxInt64, 0).Div(NewFixed(1, 0), 0).Equal(NewFixed(math.MaxInt64, 0)))
+
+	assert.True(t, NewFixed(125, 2).Rescale(1).Equal(NewFixed(13, 1)))
+	assert.True(t, NewFixed(-125, 2).Rescale(1).Equal(NewFixed(-13, 1)))
+	assert.True(t, NewFixed(125, 2).Rescale(4).Equal(NewFixed(12500, 4)))
+
+	//
+	// Add, Sub and aligning the scales panic on overflow, but comparison does
+	// not.
+	//
+	assert.Panics(t, func() { NewFixed(math.MaxInt64, 0).Add(NewFixed(1, 0)) })
+	assert.Panics(t, func() { NewFixed(math.MinInt64, 0).Sub(NewFixed(1, 0)) })
+	assert.Panics(t, func() { NewFixed(0, 0).Sub(NewFixed(math.MinInt64, 0)) })
+	assert.Panics(t, func() { NewFixed(math.MaxInt64, 0).Rescale(1) })
+	assert.Equal(t, 1, NewFixed(math.MaxInt64, 0).Cmp(NewFixed(1, 18)))
+	assert.Equal(t, -1, NewFixed(math.MinInt64, 0).Cmp(NewFixed(-1, 18)))
+	assert.Equal(t, -1, NewFixed(-1, 18).Cmp(NewFixed(0, 0)))
+	assert.True(t, NewFixed(1, 0).Equal(NewFixed(1e18, 18)))
+
+}
+
+func TestFixedMatchesDecimal(t *testing.T) {
+
+	cases := []struct {
+		cumQty, avgPx, lastQty, lastPx string
+	}{
+		{"100", "42.15", "50", "42.16"},
+		{"3", "0.0001", "7", "0.0003"},
+		{"-20", "101.5", "-30", "101.25"},
+	}
+
+	for _, c := range cases {
+		cumQty, avgPx := decimal.RequireFromString(c.cumQty), decimal.RequireFromString(c.avgPx)
+		lastQty, lastPx := decimal.RequireFromString(c.lastQty), decimal.RequireFromString(c.lastPx)
+		expectedQty, expectedPx := CumQtyAvgPx(cumQty, avgPx, lastQty, lastPx, 5)
+
+		fixed := func(d decimal.Decimal) Fixed {
+			f, err := FixedFromDecimal(d, 4)
+			assert.Nil(t, err)
+			return f
+		}
+		qty, px := FixedCumQtyAvgPx(fixed(cumQty), fixed(avgPx), fixed(lastQty), fixed(lastPx), 5)
+		assert.True(t, qty.Decimal().Equal(expectedQty), c)
+		assert.True(t, px.Decimal().Equal(expectedPx), "%v %s %s", c, px, expectedPx)
+	}
+
+	assert.True(t, FixedUnits(NewFixed(4567, 2), NewFixed(5, 1), Fixed{}).Equal(NewFixed(455, 1)))
+	assert.True(t, FixedUnits(NewFixed(4567, 2), NewFixed(5, 1), NewFixed(50, 0)).IsZero())
+	assert.True(t, FixedUnits(NewFixed(-1, 0), NewFixed(5, 1), Fixed{}).IsZero())
+	assert.True(t, FixedUnits(NewFixed(4567, 2), Fixed{}, Fixed{}).Equal(NewFixed(4567, 2)))
+
+}
+
+func TestFixedTradeAggregate(t *testing.T) {
+
+	listing := &Listing{Symbol: "A", TickIncrement: decimal.New(1, -2), RoundLot: DecimalOne}
+
+	first, err := NewFixedTrade(&Trade{Symbol: "A", LastQty: decimal.New(100, 0), LastPx: decimal.New(4215, -2)}, listing)
+	assert.Nil(t, err)
+	second, err := NewFixedTrade(&Trade{Symbol: "A", LastQty: decimal.New(50, 0), LastPx: decimal.New(4218, -2)}, listing)
+	assert.Nil(t, err)
+	_, err = NewFixedTrade(&Trade{Symbol: "A", LastQty: decimal.New(1, -1)}, listing)
+	assert.NotNil(t, err)
+
+	expected := first.Trade()
+	expected.Aggregate(second.Trade(), 3)
+
+	first.Aggregate(second, 3)
+	aggregated := first.Trade()
+	assert.True(t, aggregated.TradeVolume.Equal(expected.TradeVolume))
+	assert.True(t, aggregated.AvgPx.Equal(expected.AvgPx))
+	assert.True(t, aggregated.LastPx.Equal(expected.LastPx))
+
+}
+
+func TestFixedCryptoNotional(t *testing.T) {
+
+	//
+	// With a tick of 0.01 and a lot of 1e-8 a notional of 10000 at 100000 is
+	// beyond an int64 at scale 10.
+	//
+	listing := &Listing{Symbol: "BTC", TickIncrement: decimal.New(1, -2), RoundLot: decimal.New(1, -8)}
+
+	first, err := NewFixedTrade(&Trade{Symbol: "BTC", LastQty: decimal.New(5000, 0), LastPx: decimal.New(100000, 0)}, listing)
+	assert.Nil(t, err)
+	second, err := NewFixedTrade(&Trade{Symbol: "BTC", LastQty: decimal.New(5000, 0), LastPx: decimal.New(100001, 0)}, listing)
+	assert.Nil(t, err)
+	first.Aggregate(second, 3)
+	assert.True(t, first.TradeVolume.Equal(NewFixed(10000, 0)))
+	assert.True(t, first.AvgPx.Equal(NewFixed(1000005, 1)), first.AvgPx.String())
+
+	third, err := NewFixedTrade(&Trade{Symbol: "BTC", LastQty: decimal.New(10000, 0), LastPx: decimal.New(100000, 0)}, listing)
+	assert.Nil(t, err)
+	first.Aggregate(third, 3)
+	assert.True(t, first.TradeVolume.Equal(NewFixed(20000, 0)))
+	assert.True(t, first.AvgPx.Equal(NewFixed(10000025, 2)), first.AvgPx.String())
+
+	cases := []struct {
+		cumQty, avgPx, lastQty, lastPx string
+		n                              int32
+	}{
+		{"12345.67890123", "98765.432", "0.00000001", "99999.99", 3},
+		{"-9000", "100000.005", "-9000", "100000.01", 3},
+		{"9000.00000001", "100000.005", "0.00000002", "100000.01", 1},
+		{"9000", "-100000.015", "9000", "-100000.01", 2},
+	}
+	for _, c := range cases {
+		cumQty, avgPx := decimal.RequireFromString(c.cumQty), decimal.RequireFromString(c.avgPx)
+		lastQty, lastPx := decimal.RequireFromString(c.lastQty), decimal.RequireFromString(c.lastPx)
+		_, expectedPx := CumQtyAvgPx(cumQty, avgPx, lastQty, lastPx, c.n)
+
+		fixed := func(d decimal.Decimal, scale int32) Fixed {
+			f, err := FixedFromDecimal(d, scale)
+			assert.Nil(t, err)
+			return f
+		}
+		_, px := FixedCumQtyAvgPx(fixed(cumQty, 8), fixed(avgPx, 3), fixed(lastQty, 8), fixed(lastPx, 2), c.n)
+		assert.True(t, px.Decimal().Equal(expectedPx), "%v %s %s", c, px, expectedPx)
+	}
+
+	assert.Panics(t, func() {
+		FixedCumQtyAvgPx(NewFixed(1, 8), NewFixed(math.MaxInt64, 2), NewFixed(1, 8), NewFixed(math.MaxInt64, 2), 3)
+	}, "the average overflows")
+
+}
+
+func TestFixedPositionTraded(t *testing.T) {
+
+	listing := &Listing{
+		Symbol:             "A",
+		TickIncrement:      decimal.New(1, -2),
+		RoundLot:           DecimalOne,
+		ContractMultiplier: decimal.New(10, 0),
+	}
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(listing)
+	position := NewPosition("A", whitelist)
+
+	fixed, err := NewFixedPosition(listing)
+	assert.Nil(t, err)
+
+	trades := []struct {
+		side    Side
+		qty, px int64
+		pxScale int32
+	}{
+		{Buy, 100, 4215, 2},
+		{Buy, 50, 4218, 2},
+		{Sell, 30, 4230, 2},
+		{Sell, 200, 4201, 2},
+		{Buy, 80, 4199, 2},
+	}
+	for _, trade := range trades {
+		position.Traded(trade.side, decimal.New(trade.qty, 0), decimal.New(trade.px, -trade.pxScale))
+		fixed.Traded(trade.side, NewFixed(trade.qty, 0), NewFixed(trade.px, trade.pxScale))
+		expected, actual := position.Memo(), fixed.Memo()
+		assert.True(t, expected.Quantity.Equal(actual.Quantity))
+		assert.True(t, expected.AvgPx.Equal(actual.AvgPx), "%s %s", expected.AvgPx, actual.AvgPx)
+		assert.True(t, expected.Realised.Equal(actual.Realised), "%s %s", expected.Realised, actual.Realised)
+	}
+
+}
+
+func TestFixedAllocations(t *testing.T) {
+
+	cumQty, avgPx := NewFixed(100, 0), NewFixed(4215, 2)
+	lastQty, lastPx := NewFixed(50, 0), NewFixed(4218, 2)
+	position := &FixedPosition{precision: 3, multiplier: NewFixed(1, 0)}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		FixedCumQtyAvgPx(cumQty, avgPx, lastQty, lastPx, 3)
+		FixedUnits(lastPx, NewFixed(5, 2), Fixed{})
+		position.Traded(Buy, lastQty, lastPx)
+		position.Traded(Sell, lastQty, lastPx)
+	})
+	assert.Equal(t, 0.0, allocs)
+
+}
+
+func BenchmarkCumQtyAvgPx(b *testing.B) {
+	cumQty, avgPx := decimal.New(100, 0), decimal.New(4215, -2)
+	lastQty, lastPx := decimal.New(50, 0), decimal.New(4218, -2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		CumQtyAvgPx(cumQty, avgPx, lastQty, lastPx, 3)
+	}
+}
+
+func BenchmarkFixedCumQtyAvgPx(b *testing.B) {
+	cumQty, avgPx := NewFixed(100, 0), NewFixed(4215, 2)
+	lastQty, lastPx := NewFixed(50, 0), NewFixed(4218, 2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FixedCumQtyAvgPx(cumQty, avgPx, lastQty, lastPx, 3)
+	}
+}
+
+func BenchmarkUnits(b *testing.B) {
+	x, unit := decimal.New(4567, -2), decimal.New(5, -2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Units(x, unit, decimal.Zero)
+	}
+}
+
+func BenchmarkFixedUnits(b *testing.B) {
+	x, unit := NewFixed(4567, 2), NewFixed(5, 2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FixedUnits(x, unit, Fixed{})
+	}
+}
+
+func BenchmarkPositionTraded(b *testing.B) {
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A", TickIncrement: decimal.New(1, -2), ContractMultiplier: DecimalOne})
+	position := NewPosition("A", whitelist)
+	qty, px := decimal.New(50, 0), decimal.New(4218, -2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		position.Traded(Buy, qty, px)
+		position.Traded(Sell, qty, px)
+	}
+}
+
+func BenchmarkFixedPositionTraded(b *testing.B) {
+	position, _ := NewFixedPosition(&Listing{Symbol: "A", TickIncrement: decimal.New(1, -2)})
+	qty, px := NewFixed(50, 0), NewFixed(4218, 2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		position.Traded(Buy, qty, px)
+		position.Traded(Sell, qty, px)
+	}
+}
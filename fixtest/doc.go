@@ -0,0 +1,3 @@
+// Package fixtest provides a local FIX acceptor, backed by [mkt.Engine], so
+// that order routing code can be tested end to end without a real broker.
+package fixtest
@@ -0,0 +1,101 @@
+package fixtest
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gbkr-com/mkt"
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/tag"
+)
+
+// Message returns the NewOrderSingle, OrderCancelRequest or
+// OrderCancelReplaceRequest for the ticket, according to its MsgType. The
+// origClOrdID is ignored for a new order.
+func Message(ticket *mkt.Ticket, clOrdID, origClOrdID string) *quickfix.Message {
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(ticket.MsgType.AsQuickFIX())
+
+	msg.Body.Set(field.NewClOrdID(clOrdID))
+	if ticket.MsgType != mkt.OrderNew {
+		msg.Body.Set(field.NewOrigClOrdID(origClOrdID))
+	}
+	msg.Body.Set(field.NewSymbol(ticket.Symbol))
+	msg.Body.Set(ticket.Side.AsQuickFIX())
+	msg.Body.Set(field.NewTransactTime(time.Now().UTC()))
+	msg.Body.Set(field.NewOrderQty(ticket.OrderQty, mkt.Precision(ticket.OrderQty)))
+
+	if ticket.MsgType == mkt.OrderCancel {
+		return msg
+	}
+
+	if ticket.Price.IsZero() {
+		msg.Body.Set(field.NewOrdType(enum.OrdType_MARKET))
+	} else {
+		msg.Body.Set(field.NewOrdType(enum.OrdType_LIMIT))
+		msg.Body.Set(field.NewPrice(ticket.Price, mkt.Precision(ticket.Price)))
+	}
+	if ticket.TimeInForce != 0 {
+		msg.Body.Set(ticket.TimeInForce.AsQuickFIX())
+	}
+
+	return msg
+
+}
+
+// DecodeReport returns the [*mkt.Report] in an ExecutionReport.
+func DecodeReport(msg *quickfix.Message) (*mkt.Report, error) {
+
+	if !msg.IsMsgTypeOf(string(enum.MsgType_EXECUTION_REPORT)) {
+		return nil, errors.New("fixtest: not an ExecutionReport")
+	}
+
+	report := &mkt.Report{}
+
+	var err quickfix.MessageRejectError
+	if report.OrderID, err = msg.Body.GetString(tag.OrderID); err != nil {
+		return nil, err
+	}
+	report.Symbol, _ = msg.Body.GetString(tag.Symbol)
+	report.SecondaryOrderID, _ = msg.Body.GetString(tag.SecondaryOrderID)
+	report.ClOrdID, _ = msg.Body.GetString(tag.ClOrdID)
+	report.Account, _ = msg.Body.GetString(tag.Account)
+	report.ExecInst, _ = msg.Body.GetString(tag.ExecInst)
+	report.ExecID, _ = msg.Body.GetString(tag.ExecID)
+	report.ExecRefID, _ = msg.Body.GetString(tag.ExecRefID)
+
+	var side field.SideField
+	if msg.Body.Get(&side) == nil {
+		report.Side = mkt.SideFromFIX(side)
+	}
+	var ordStatus field.OrdStatusField
+	if msg.Body.Get(&ordStatus) == nil {
+		report.OrdStatus = mkt.OrdStatusFromFIX(ordStatus)
+	}
+	var execType field.ExecTypeField
+	if msg.Body.Get(&execType) == nil {
+		report.ExecType = mkt.ExecTypeFromFIX(execType)
+	}
+	var timeInForce field.TimeInForceField
+	if msg.Body.Get(&timeInForce) == nil {
+		report.TimeInForce = mkt.TimeInForceFromFIX(timeInForce)
+	}
+	var lastQty field.LastQtyField
+	if msg.Body.Get(&lastQty) == nil {
+		report.LastQty = lastQty.Value()
+	}
+	var lastPx field.LastPxField
+	if msg.Body.Get(&lastPx) == nil {
+		report.LastPx = lastPx.Value()
+	}
+	var transactTime field.TransactTimeField
+	if msg.Body.Get(&transactTime) == nil {
+		report.TransactTime = transactTime.Value()
+	}
+
+	return report, nil
+
+}
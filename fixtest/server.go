@@ -0,0 +1,483 @@
+package fixtest
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gbkr-com/mkt"
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/tag"
+	"github.com/shopspring/decimal"
+)
+
+// Default session identity.
+const (
+	BeginString  = "FIX.4.4"
+	SenderCompID = "BROKER" // The acceptor.
+	TargetCompID = "CLIENT" // The initiator under test.
+)
+
+// Scenario scripts the response of the [Server] to a NewOrderSingle.
+type Scenario int
+
+// Supported Scenario values. The zero value is to work the order in the
+// matching engine.
+const (
+	Match             Scenario = iota // Work the order in the engine.
+	Reject                            // Reject the order.
+	PartialFill                       // Acknowledge, then fill half the order, in round lots, at its price.
+	BustedTrade                       // Acknowledge, fill, then bust the fill.
+	UnsolicitedCancel                 // Acknowledge, then cancel.
+)
+
+// Server is a FIX acceptor on localhost. Orders are worked in an [mkt.Engine]
+// for each listing in the white list unless a [Scenario] has been scripted.
+type Server[T mkt.AnyListing] struct {
+	port      int
+	sessionID quickfix.SessionID
+	acceptor  *quickfix.Acceptor
+	whitelist *mkt.WhiteList[T]
+	engines   map[string]*mkt.Engine[T]
+	reports   chan *mkt.Report
+	orders    map[string]*order // By OrderID.
+	clOrdIDs  map[string]string // ClOrdID to OrderID.
+	script    []Scenario
+	execs     int64
+	lock      sync.Mutex
+}
+
+type order struct {
+	ticket           mkt.Ticket
+	clOrdID          string
+	secondaryOrderID string
+	cumQty           decimal.Decimal
+	avgPx            decimal.Decimal
+	status           mkt.OrdStatus
+	scripted         bool
+}
+
+// NewServer starts a [*Server] listening on a free port on localhost.
+func NewServer[T mkt.AnyListing](whitelist *mkt.WhiteList[T]) (*Server[T], error) {
+
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	server := &Server[T]{
+		port:      port,
+		whitelist: whitelist,
+		engines:   map[string]*mkt.Engine[T]{},
+		reports:   make(chan *mkt.Report, 1024),
+		orders:    map[string]*order{},
+		clOrdIDs:  map[string]string{},
+	}
+
+	settings := quickfix.NewSettings()
+	settings.GlobalSettings().Set(config.SocketAcceptHost, "127.0.0.1")
+	settings.GlobalSettings().Set(config.SocketAcceptPort, strconv.Itoa(port))
+	session := quickfix.NewSessionSettings()
+	session.Set(config.BeginString, BeginString)
+	session.Set(config.SenderCompID, SenderCompID)
+	session.Set(config.TargetCompID, TargetCompID)
+	session.Set(config.ResetOnLogon, "Y")
+	if server.sessionID, err = settings.AddSession(session); err != nil {
+		return nil, err
+	}
+
+	server.acceptor, err = quickfix.NewAcceptor(&application[T]{server}, quickfix.NewMemoryStoreFactory(), settings, quickfix.NewNullLogFactory())
+	if err != nil {
+		return nil, err
+	}
+	if err = server.acceptor.Start(); err != nil {
+		return nil, err
+	}
+	return server, nil
+
+}
+
+// ClientSettings returns the settings for a QuickFIX initiator to connect to
+// this server.
+func (x *Server[T]) ClientSettings() *quickfix.Settings {
+	settings := quickfix.NewSettings()
+	session := quickfix.NewSessionSettings()
+	session.Set(config.BeginString, BeginString)
+	session.Set(config.SenderCompID, TargetCompID)
+	session.Set(config.TargetCompID, SenderCompID)
+	session.Set(config.SocketConnectHost, "127.0.0.1")
+	session.Set(config.SocketConnectPort, strconv.Itoa(x.port))
+	session.Set(config.HeartBtInt, "30")
+	session.Set(config.ReconnectInterval, "1")
+	session.Set(config.ResetOnLogon, "Y")
+	_, _ = settings.AddSession(session)
+	return settings
+}
+
+// Close stops the server.
+func (x *Server[T]) Close() {
+	x.acceptor.Stop()
+	_ = quickfix.UnregisterSession(x.sessionID)
+}
+
+// Script the responses to the next NewOrderSingle messages, in order. Once the
+// script is exhausted orders are worked in the matching engine.
+func (x *Server[T]) Script(scenarios ...Scenario) {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	x.script = append(x.script, scenarios...)
+}
+
+// Seed submits a ticket directly to the matching engine, for example to
+// provide liquidity. Reports for seeded orders are not sent to the client, so
+// their OrderID values must not collide with those of the client.
+func (x *Server[T]) Seed(ticket *mkt.Ticket) error {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	engine, err := x.engine(ticket.Symbol)
+	if err != nil {
+		return err
+	}
+	err = engine.Submit(ticket)
+	x.drain(0, "")
+	return err
+}
+
+func (x *Server[T]) engine(symbol string) (*mkt.Engine[T], error) {
+	engine, ok := x.engines[symbol]
+	if ok {
+		return engine, nil
+	}
+	listing, ok := x.whitelist.Lookup(symbol)
+	if !ok {
+		return nil, fmt.Errorf("fixtest: %s is not whitelisted", symbol)
+	}
+	engine = mkt.NewEngine(listing, mkt.WithEngineReports[T](x.reports))
+	x.engines[symbol] = engine
+	return engine, nil
+}
+
+func (x *Server[T]) onMessage(msg *quickfix.Message) {
+
+	x.lock.Lock()
+	defer x.lock.Unlock()
+
+	ticket, clOrdID, origClOrdID, err := ticketFrom(msg)
+	if err != nil {
+		return
+	}
+
+	switch ticket.MsgType {
+	case mkt.OrderNew:
+		x.onNew(ticket, clOrdID)
+	case mkt.OrderCancel, mkt.OrderReplace:
+		x.onAmend(ticket, clOrdID, origClOrdID)
+	}
+
+}
+
+func (x *Server[T]) onNew(ticket *mkt.Ticket, clOrdID string) {
+
+	ticket.OrderID = clOrdID
+	o := &order{ticket: *ticket, clOrdID: clOrdID}
+
+	scenario := Match
+	if len(x.script) > 0 {
+		scenario, x.script = x.script[0], x.script[1:]
+	}
+
+	engine, err := x.engine(ticket.Symbol)
+	if err != nil || x.orders[clOrdID] != nil {
+		scenario = Reject
+	}
+
+	if scenario == Match {
+		x.orders[clOrdID] = o
+		x.clOrdIDs[clOrdID] = clOrdID
+		_ = engine.Submit(ticket)
+		x.drain(0, "")
+		return
+	}
+
+	o.scripted = true
+	x.execs++
+	o.secondaryOrderID = "S" + strconv.FormatInt(x.execs, 10)
+	if scenario == Reject {
+		o.status = mkt.OrdStatusRejected
+		x.send(o, enum.ExecType_REJECTED, decimal.Zero, decimal.Zero, "")
+		return
+	}
+
+	x.orders[clOrdID] = o
+	x.clOrdIDs[clOrdID] = clOrdID
+
+	o.status = mkt.OrdStatusNew
+	x.send(o, enum.ExecType_NEW, decimal.Zero, decimal.Zero, "")
+
+	switch scenario {
+	case PartialFill:
+		listing, _ := x.whitelist.Lookup(ticket.Symbol)
+		def := listing.Definition()
+		half := mkt.Units(ticket.OrderQty.Div(decimal.New(2, 0)), def.RoundLot, def.MinTradeVol)
+		if half.IsZero() {
+			half = ticket.OrderQty
+		}
+		o.fill(half, ticket.Price)
+		x.send(o, enum.ExecType_TRADE, half, ticket.Price, "")
+
+	case BustedTrade:
+		o.fill(ticket.OrderQty, ticket.Price)
+		execID := x.send(o, enum.ExecType_TRADE, ticket.OrderQty, ticket.Price, "")
+		o.cumQty, o.avgPx = decimal.Zero, decimal.Zero
+		o.status = mkt.OrdStatusCanceled
+		x.send(o, enum.ExecType_TRADE_CANCEL, ticket.OrderQty, ticket.Price, execID)
+
+	case UnsolicitedCancel:
+		o.status = mkt.OrdStatusCanceled
+		x.send(o, enum.ExecType_CANCELED, decimal.Zero, decimal.Zero, "")
+	}
+
+}
+
+func (x *Server[T]) onAmend(ticket *mkt.Ticket, clOrdID, origClOrdID string) {
+
+	orderID, ok := x.clOrdIDs[origClOrdID]
+	o := x.orders[orderID]
+	if !ok || o == nil || o.done() {
+		x.cancelReject(ticket, clOrdID, origClOrdID, orderID, mkt.OrdStatusRejected)
+		return
+	}
+
+	ticket.OrderID = orderID
+
+	if !o.scripted {
+		engine, _ := x.engine(o.ticket.Symbol)
+		if err := engine.Submit(ticket); err != nil {
+			//
+			// The engine also reports the refusal, which is sent as the
+			// cancel reject instead.
+			//
+			for len(x.reports) > 0 {
+				<-x.reports
+			}
+			x.cancelReject(ticket, clOrdID, origClOrdID, orderID, o.status)
+			return
+		}
+		o.clOrdID = clOrdID
+		x.clOrdIDs[clOrdID] = orderID
+		if ticket.MsgType == mkt.OrderReplace {
+			o.ticket.OrderQty, o.ticket.Price, o.ticket.TimeInForce = ticket.OrderQty, ticket.Price, ticket.TimeInForce
+		}
+		x.drain(ticket.MsgType, orderID)
+		return
+	}
+
+	o.clOrdID = clOrdID
+	x.clOrdIDs[clOrdID] = orderID
+	if ticket.MsgType == mkt.OrderCancel {
+		o.status = mkt.OrdStatusCanceled
+		x.send(o, enum.ExecType_CANCELED, decimal.Zero, decimal.Zero, "")
+		return
+	}
+	o.ticket.OrderQty, o.ticket.Price, o.ticket.TimeInForce = ticket.OrderQty, ticket.Price, ticket.TimeInForce
+	x.send(o, enum.ExecType_REPLACED, decimal.Zero, decimal.Zero, "")
+
+}
+
+// drain the engine reports, forwarding those for client orders. The msgType and
+// orderID identify a cancel or replace that is being acknowledged.
+func (x *Server[T]) drain(msgType mkt.MsgType, orderID string) {
+	for len(x.reports) > 0 {
+		report := <-x.reports
+		o := x.orders[report.OrderID]
+		if o == nil || o.scripted {
+			continue
+		}
+		o.status = report.OrdStatus
+		o.secondaryOrderID = report.SecondaryOrderID
+
+		switch {
+		case report.LastQty.IsPositive():
+			o.fill(report.LastQty, report.LastPx)
+			o.status = report.OrdStatus
+			x.send(o, enum.ExecType_TRADE, report.LastQty, report.LastPx, "")
+		case report.OrderID == orderID && msgType == mkt.OrderReplace:
+			x.send(o, enum.ExecType_REPLACED, decimal.Zero, decimal.Zero, "")
+			msgType = 0
+		case report.OrdStatus == mkt.OrdStatusCanceled:
+			x.send(o, enum.ExecType_CANCELED, decimal.Zero, decimal.Zero, "")
+		case report.OrdStatus == mkt.OrdStatusRejected:
+			x.send(o, enum.ExecType_REJECTED, decimal.Zero, decimal.Zero, "")
+		default:
+			x.send(o, enum.ExecType_NEW, decimal.Zero, decimal.Zero, "")
+		}
+	}
+}
+
+func (o *order) fill(lastQty, lastPx decimal.Decimal) {
+	o.cumQty, o.avgPx = mkt.CumQtyAvgPx(o.cumQty, o.avgPx, lastQty, lastPx, mkt.Precision(lastPx)+4)
+	o.status = mkt.OrdStatusFilled
+	if o.cumQty.LessThan(o.ticket.OrderQty) {
+		o.status = mkt.OrdStatusPartiallyFilled
+	}
+}
+
+func (o *order) done() bool {
+	switch o.status {
+	case mkt.OrdStatusFilled, mkt.OrdStatusCanceled, mkt.OrdStatusRejected, mkt.OrdStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+func (o *order) leavesQty() decimal.Decimal {
+	if o.done() {
+		return decimal.Zero
+	}
+	return o.ticket.OrderQty.Sub(o.cumQty)
+}
+
+// send an ExecutionReport for the order and return its ExecID.
+func (x *Server[T]) send(o *order, execType enum.ExecType, lastQty, lastPx decimal.Decimal, execRefID string) string {
+
+	x.execs++
+	execID := strconv.FormatInt(x.execs, 10)
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_EXECUTION_REPORT))
+	msg.Body.Set(field.NewOrderID(o.ticket.OrderID))
+	if o.secondaryOrderID != "" {
+		msg.Body.Set(field.NewSecondaryOrderID(o.secondaryOrderID))
+	}
+	msg.Body.Set(field.NewClOrdID(o.clOrdID))
+	msg.Body.Set(field.NewExecID(execID))
+	if execRefID != "" {
+		msg.Body.Set(field.NewExecRefID(execRefID))
+	}
+	msg.Body.Set(field.NewExecType(execType))
+	msg.Body.Set(o.status.AsQuickFIX())
+	msg.Body.Set(field.NewSymbol(o.ticket.Symbol))
+	msg.Body.Set(o.ticket.Side.AsQuickFIX())
+	msg.Body.Set(field.NewOrderQty(o.ticket.OrderQty, mkt.Precision(o.ticket.OrderQty)))
+	if o.ticket.TimeInForce != 0 {
+		msg.Body.Set(o.ticket.TimeInForce.AsQuickFIX())
+	}
+	msg.Body.Set(field.NewLastQty(lastQty, mkt.Precision(lastQty)))
+	msg.Body.Set(field.NewLastPx(lastPx, mkt.Precision(lastPx)))
+	leavesQty := o.leavesQty()
+	msg.Body.Set(field.NewLeavesQty(leavesQty, mkt.Precision(leavesQty)))
+	msg.Body.Set(field.NewCumQty(o.cumQty, mkt.Precision(o.cumQty)))
+	msg.Body.Set(field.NewAvgPx(o.avgPx, mkt.Precision(o.avgPx)))
+	msg.Body.Set(field.NewTransactTime(time.Now().UTC()))
+
+	_ = quickfix.SendToTarget(msg, x.sessionID)
+	return execID
+
+}
+
+func (x *Server[T]) cancelReject(ticket *mkt.Ticket, clOrdID, origClOrdID, orderID string, status mkt.OrdStatus) {
+
+	responseTo := enum.CxlRejResponseTo_ORDER_CANCEL_REQUEST
+	if ticket.MsgType == mkt.OrderReplace {
+		responseTo = enum.CxlRejResponseTo_ORDER_CANCEL_REPLACE_REQUEST
+	}
+	if orderID == "" {
+		orderID = "NONE"
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_ORDER_CANCEL_REJECT))
+	msg.Body.Set(field.NewOrderID(orderID))
+	msg.Body.Set(field.NewClOrdID(clOrdID))
+	msg.Body.Set(field.NewOrigClOrdID(origClOrdID))
+	msg.Body.Set(status.AsQuickFIX())
+	msg.Body.Set(field.NewCxlRejResponseTo(responseTo))
+
+	_ = quickfix.SendToTarget(msg, x.sessionID)
+
+}
+
+// ticketFrom reads an order request into a ticket, returning also the ClOrdID
+// and OrigClOrdID.
+func ticketFrom(msg *quickfix.Message) (*mkt.Ticket, string, string, error) {
+
+	ticket := &mkt.Ticket{}
+
+	msgType, err := msg.MsgType()
+	if err != nil {
+		return nil, "", "", err
+	}
+	switch enum.MsgType(msgType) {
+	case enum.MsgType_ORDER_SINGLE:
+		ticket.MsgType = mkt.OrderNew
+	case enum.MsgType_ORDER_CANCEL_REQUEST:
+		ticket.MsgType = mkt.OrderCancel
+	case enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST:
+		ticket.MsgType = mkt.OrderReplace
+	default:
+		return nil, "", "", fmt.Errorf("fixtest: unsupported MsgType %s", msgType)
+	}
+
+	clOrdID, err := msg.Body.GetString(tag.ClOrdID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	origClOrdID, _ := msg.Body.GetString(tag.OrigClOrdID)
+
+	ticket.Symbol, _ = msg.Body.GetString(tag.Symbol)
+	var side field.SideField
+	if msg.Body.Get(&side) == nil {
+		ticket.Side = mkt.SideFromFIX(side)
+	}
+	var orderQty field.OrderQtyField
+	if msg.Body.Get(&orderQty) == nil {
+		ticket.OrderQty = orderQty.Value()
+	}
+	var price field.PriceField
+	if msg.Body.Get(&price) == nil {
+		ticket.Price = price.Value()
+	}
+	var timeInForce field.TimeInForceField
+	if msg.Body.Get(&timeInForce) == nil {
+		ticket.TimeInForce = mkt.TimeInForceFromFIX(timeInForce)
+	}
+
+	return ticket, clOrdID, origClOrdID, nil
+
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// application adapts the server to [quickfix.Application].
+type application[T mkt.AnyListing] struct {
+	server *Server[T]
+}
+
+func (x *application[T]) OnCreate(quickfix.SessionID)                   {}
+func (x *application[T]) OnLogon(quickfix.SessionID)                    {}
+func (x *application[T]) OnLogout(quickfix.SessionID)                   {}
+func (x *application[T]) ToAdmin(*quickfix.Message, quickfix.SessionID) {}
+
+func (x *application[T]) ToApp(*quickfix.Message, quickfix.SessionID) error { return nil }
+
+func (x *application[T]) FromAdmin(*quickfix.Message, quickfix.SessionID) quickfix.MessageRejectError {
+	return nil
+}
+
+func (x *application[T]) FromApp(msg *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+	x.server.onMessage(msg)
+	return nil
+}
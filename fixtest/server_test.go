@@ -0,0 +1,189 @@
+package fixtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gbkr-com/mkt"
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type client struct {
+	logon   chan struct{}
+	reports chan *quickfix.Message
+}
+
+func (x *client) OnCreate(quickfix.SessionID)                   {}
+func (x *client) OnLogon(quickfix.SessionID)                    { x.logon <- struct{}{} }
+func (x *client) OnLogout(quickfix.SessionID)                   {}
+func (x *client) ToAdmin(*quickfix.Message, quickfix.SessionID) {}
+
+func (x *client) ToApp(*quickfix.Message, quickfix.SessionID) error { return nil }
+
+func (x *client) FromAdmin(*quickfix.Message, quickfix.SessionID) quickfix.MessageRejectError {
+	return nil
+}
+
+func (x *client) FromApp(msg *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+	x.reports <- msg
+	return nil
+}
+
+func (x *client) next(t *testing.T) *quickfix.Message {
+	select {
+	case msg := <-x.reports:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a message")
+		return nil
+	}
+}
+
+func (x *client) report(t *testing.T) (*mkt.Report, enum.ExecType) {
+	msg := x.next(t)
+	report, err := DecodeReport(msg)
+	require.Nil(t, err)
+	var execType field.ExecTypeField
+	require.Nil(t, msg.Body.Get(&execType))
+	return report, execType.Value()
+}
+
+func ticket(msgType mkt.MsgType, side mkt.Side, qty, price int64) *mkt.Ticket {
+	return &mkt.Ticket{
+		Order:       mkt.Order{MsgType: msgType, Side: side, Symbol: "A"},
+		OrderQty:    decimal.New(qty, 0),
+		Price:       decimal.New(price, 0),
+		TimeInForce: mkt.GTC,
+	}
+}
+
+func TestServer(t *testing.T) {
+
+	whitelist := mkt.NewWhiteList[*mkt.Listing]()
+	whitelist.Add(&mkt.Listing{
+		Symbol:        "A",
+		TickIncrement: decimal.New(1, 0),
+		RoundLot:      decimal.New(10, 0),
+		MinTradeVol:   decimal.New(10, 0),
+	})
+
+	server, err := NewServer(whitelist)
+	require.Nil(t, err)
+	defer server.Close()
+
+	app := &client{logon: make(chan struct{}, 1), reports: make(chan *quickfix.Message, 64)}
+	initiator, err := quickfix.NewInitiator(app, quickfix.NewMemoryStoreFactory(), server.ClientSettings(), quickfix.NewNullLogFactory())
+	require.Nil(t, err)
+	require.Nil(t, initiator.Start())
+	sessionID := quickfix.SessionID{BeginString: BeginString, SenderCompID: TargetCompID, TargetCompID: SenderCompID}
+	defer func() {
+		initiator.Stop()
+		_ = quickfix.UnregisterSession(sessionID)
+	}()
+
+	select {
+	case <-app.logon:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for logon")
+	}
+
+	send := func(ticket *mkt.Ticket, clOrdID, origClOrdID string) {
+		require.Nil(t, quickfix.SendToTarget(Message(ticket, clOrdID, origClOrdID), sessionID))
+	}
+
+	//
+	// Matched in the engine against seeded liquidity.
+	//
+	seed := ticket(mkt.OrderNew, mkt.Sell, 10, 42)
+	seed.OrderID = "SEED"
+	require.Nil(t, server.Seed(seed))
+
+	send(ticket(mkt.OrderNew, mkt.Buy, 20, 42), "1", "")
+	report, execType := app.report(t)
+	assert.Equal(t, enum.ExecType_NEW, execType)
+	assert.Equal(t, "1", report.OrderID)
+	assert.Equal(t, mkt.Buy, report.Side)
+	report, execType = app.report(t)
+	assert.Equal(t, enum.ExecType_TRADE, execType)
+	assert.Equal(t, mkt.OrdStatusPartiallyFilled, report.OrdStatus)
+	assert.True(t, report.LastQty.Equal(decimal.New(10, 0)))
+	assert.True(t, report.LastPx.Equal(decimal.New(42, 0)))
+
+	send(ticket(mkt.OrderReplace, mkt.Buy, 30, 41), "2", "1")
+	report, execType = app.report(t)
+	assert.Equal(t, enum.ExecType_REPLACED, execType)
+	assert.Equal(t, "1", report.OrderID)
+	assert.Equal(t, "2", report.ClOrdID)
+
+	send(ticket(mkt.OrderCancel, mkt.Buy, 30, 0), "3", "2")
+	report, execType = app.report(t)
+	assert.Equal(t, enum.ExecType_CANCELED, execType)
+	assert.Equal(t, mkt.OrdStatusCanceled, report.OrdStatus)
+
+	send(ticket(mkt.OrderCancel, mkt.Buy, 30, 0), "4", "3")
+	msg := app.next(t)
+	assert.True(t, msg.IsMsgTypeOf(string(enum.MsgType_ORDER_CANCEL_REJECT)))
+
+	//
+	// Scripted scenarios.
+	//
+	server.Script(Reject, PartialFill, BustedTrade, UnsolicitedCancel)
+
+	send(ticket(mkt.OrderNew, mkt.Sell, 20, 42), "5", "")
+	report, _ = app.report(t)
+	assert.Equal(t, mkt.OrdStatusRejected, report.OrdStatus)
+
+	send(ticket(mkt.OrderNew, mkt.Sell, 20, 42), "6", "")
+	_, execType = app.report(t)
+	assert.Equal(t, enum.ExecType_NEW, execType)
+	report, _ = app.report(t)
+	assert.Equal(t, mkt.OrdStatusPartiallyFilled, report.OrdStatus)
+	assert.True(t, report.LastQty.Equal(decimal.New(10, 0)))
+
+	send(ticket(mkt.OrderNew, mkt.Sell, 20, 42), "7", "")
+	app.report(t)
+	fill, _ := app.report(t)
+	assert.Equal(t, mkt.OrdStatusFilled, fill.OrdStatus)
+	assert.Equal(t, mkt.ExecTypeTrade, fill.ExecType)
+	report, execType = app.report(t)
+	assert.Equal(t, enum.ExecType_TRADE_CANCEL, execType)
+	assert.Equal(t, mkt.ExecTypeTradeCancel, report.ExecType)
+	assert.NotEqual(t, fill.ExecID, report.ExecID)
+	assert.Equal(t, fill.ExecID, report.ExecRefID)
+
+	send(ticket(mkt.OrderNew, mkt.Sell, 20, 42), "8", "")
+	app.report(t)
+	report, execType = app.report(t)
+	assert.Equal(t, enum.ExecType_CANCELED, execType)
+	assert.Equal(t, mkt.OrdStatusCanceled, report.OrdStatus)
+
+	//
+	// Rejected by the engine, here for an odd lot.
+	//
+	send(ticket(mkt.OrderNew, mkt.Buy, 15, 42), "9", "")
+	report, execType = app.report(t)
+	assert.Equal(t, enum.ExecType_REJECTED, execType)
+	assert.Equal(t, mkt.OrdStatusRejected, report.OrdStatus)
+	assert.Equal(t, "9", report.OrderID)
+
+	//
+	// A replace refused by the engine is a cancel reject, and the order is
+	// unchanged.
+	//
+	send(ticket(mkt.OrderNew, mkt.Buy, 10, 40), "10", "")
+	_, execType = app.report(t)
+	assert.Equal(t, enum.ExecType_NEW, execType)
+	send(ticket(mkt.OrderReplace, mkt.Buy, 15, 40), "11", "10")
+	msg = app.next(t)
+	assert.True(t, msg.IsMsgTypeOf(string(enum.MsgType_ORDER_CANCEL_REJECT)))
+	send(ticket(mkt.OrderCancel, mkt.Buy, 10, 0), "12", "10")
+	report, execType = app.report(t)
+	assert.Equal(t, enum.ExecType_CANCELED, execType)
+	assert.Equal(t, "10", report.OrderID)
+
+}
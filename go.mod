@@ -7,8 +7,11 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/quickfixgo/enum v0.1.0
 	github.com/quickfixgo/field v0.1.0
+	github.com/quickfixgo/quickfix v0.7.0
+	github.com/quickfixgo/tag v0.1.0
 	github.com/shopspring/decimal v1.3.1
 	github.com/stretchr/testify v1.9.0
+	google.golang.org/protobuf v1.36.6
 )
 
 require (
@@ -19,8 +22,6 @@ require (
 	github.com/montanaflynn/stats v0.6.6 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/quickfixgo/quickfix v0.7.0 // indirect
-	github.com/quickfixgo/tag v0.1.0 // indirect
 	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
 	github.com/xdg-go/scram v1.1.1 // indirect
 	github.com/xdg-go/stringprep v1.0.3 // indirect
@@ -0,0 +1,207 @@
+package mkt
+
+import (
+	"sort"
+	"time"
+)
+
+// Ingester applies the fills in a stream of [Report] to a [Book] exactly
+// once, so that resent or repeated reports do not double count.
+//
+// Reports are de-duplicated by ExecID; a report without one cannot be
+// recognised as a duplicate. Fills, busts and corrections are held for the
+// reorder window, then applied in TransactTime order, ties being broken by
+// the order of arrival. A bust, ExecType H, reverses the fill named by its
+// ExecRefID, and a correction, ExecType G, replaces that fill with its own
+// LastQty and LastPx, using [Book.Cancel] and [Book.Correct] with the ExecID
+// as the trade ID. A bust or correction that arrives before its fill is held
+// until the fill arrives.
+//
+// Reports that are neither fills, busts nor corrections are ignored. A report
+// with no ExecType is a fill if it has a positive LastQty.
+type Ingester[T AnyListing] struct {
+	book       *Book[T]
+	window     time.Duration
+	seen       map[string]bool      // ExecIDs already ingested.
+	symbols    map[string]string    // Symbol of each fill applied, by ExecID.
+	pending    map[string][]*Report // Busts and corrections by ExecRefID.
+	buffer     []ingested
+	latest     time.Time
+	arrivals   int64
+	duplicates int
+}
+
+type ingested struct {
+	report  *Report
+	arrival int64
+}
+
+// IngesterOption is any option that can be applied when constructing the
+// ingester.
+type IngesterOption[T AnyListing] func(*Ingester[T])
+
+// WithReorderWindow holds each report until a report with a TransactTime at
+// least the window later has arrived, so that reports up to the window out
+// of order are applied in order. The default of zero applies each report as
+// it arrives.
+func WithReorderWindow[T AnyListing](window time.Duration) IngesterOption[T] {
+	return func(x *Ingester[T]) {
+		x.window = window
+	}
+}
+
+// NewIngester returns an [*Ingester] for the book.
+func NewIngester[T AnyListing](book *Book[T], options ...IngesterOption[T]) *Ingester[T] {
+	ingester := &Ingester[T]{
+		book:    book,
+		seen:    map[string]bool{},
+		symbols: map[string]string{},
+		pending: map[string][]*Report{},
+	}
+	for _, option := range options {
+		option(ingester)
+	}
+	return ingester
+}
+
+// Ingest takes the report, applying it and any earlier reports that are now
+// outside the reorder window. The report is copied, so may be recycled once
+// this function returns. It returns the first error from the book, and a
+// report the book refused is not taken as seen, so that it may be resent.
+func (x *Ingester[T]) Ingest(report *Report) error {
+
+	switch report.ExecType {
+	case ExecTypeTrade, ExecTypeTradeCancel, ExecTypeTradeCorrect:
+	case 0:
+		if !report.LastQty.IsPositive() {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	if report.ExecID != "" {
+		if x.seen[report.ExecID] {
+			x.duplicates++
+			return nil
+		}
+		x.seen[report.ExecID] = true
+	}
+
+	copied := *report
+	x.arrivals++
+	x.buffer = append(x.buffer, ingested{report: &copied, arrival: x.arrivals})
+	if report.TransactTime.After(x.latest) {
+		x.latest = report.TransactTime
+	}
+
+	return x.release(x.latest.Add(-x.window))
+
+}
+
+// Flush applies every report still held in the reorder window.
+func (x *Ingester[T]) Flush() error {
+	return x.release(x.latest)
+}
+
+// Duplicates returns the number of reports ignored as duplicates.
+func (x *Ingester[T]) Duplicates() int {
+	return x.duplicates
+}
+
+// Pending returns the busts and corrections still waiting for their fill.
+func (x *Ingester[T]) Pending() []*Report {
+	var reports []*Report
+	for _, waiting := range x.pending {
+		reports = append(reports, waiting...)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].TransactTime.Before(reports[j].TransactTime) })
+	return reports
+}
+
+// release applies the buffered reports up to and including the time.
+func (x *Ingester[T]) release(until time.Time) error {
+
+	sort.SliceStable(x.buffer, func(i, j int) bool {
+		a, b := x.buffer[i], x.buffer[j]
+		if !a.report.TransactTime.Equal(b.report.TransactTime) {
+			return a.report.TransactTime.Before(b.report.TransactTime)
+		}
+		return a.arrival < b.arrival
+	})
+
+	n := 0
+	for n < len(x.buffer) && !x.buffer[n].report.TransactTime.After(until) {
+		n++
+	}
+	released := x.buffer[:n]
+	x.buffer = append([]ingested(nil), x.buffer[n:]...)
+
+	//
+	// Every released report is applied, even after an error, since none of
+	// them remains in the buffer.
+	//
+	var first error
+	for _, item := range released {
+		if err := x.apply(item.report); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+
+}
+
+// apply the report to the book, forgetting its ExecID if the book refuses
+// it.
+func (x *Ingester[T]) apply(report *Report) error {
+
+	if report.ExecType == ExecTypeTradeCancel || report.ExecType == ExecTypeTradeCorrect {
+		if _, ok := x.symbols[report.ExecRefID]; !ok {
+			x.pending[report.ExecRefID] = append(x.pending[report.ExecRefID], report)
+			return nil
+		}
+		return x.amend(report)
+	}
+
+	if report.ExecID == "" {
+		return x.book.Traded(report.Symbol, report.Side, report.LastQty, report.LastPx)
+	}
+	if err := x.book.TradedWithID(report.Symbol, report.ExecID, report.Side, report.LastQty, report.LastPx); err != nil {
+		delete(x.seen, report.ExecID)
+		return err
+	}
+	x.symbols[report.ExecID] = report.Symbol
+
+	waiting := x.pending[report.ExecID]
+	delete(x.pending, report.ExecID)
+	var first error
+	for _, amendment := range waiting {
+		if _, ok := x.symbols[report.ExecID]; !ok {
+			break // Busted already.
+		}
+		if err := x.amend(amendment); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+
+}
+
+// amend busts or corrects the fill named by the ExecRefID, forgetting the
+// ExecID of the amendment if the book refuses it.
+func (x *Ingester[T]) amend(amendment *Report) error {
+	symbol := x.symbols[amendment.ExecRefID]
+	var err error
+	if amendment.ExecType == ExecTypeTradeCancel {
+		err = x.book.Cancel(symbol, amendment.ExecRefID)
+		if err == nil {
+			delete(x.symbols, amendment.ExecRefID)
+		}
+	} else {
+		err = x.book.Correct(symbol, amendment.ExecRefID, amendment.LastQty, amendment.LastPx)
+	}
+	if err != nil {
+		delete(x.seen, amendment.ExecID)
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package mkt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testBookMemo(book *Book[*Listing], symbol string) *PositionMemo {
+	memo := &PositionMemo{Symbol: symbol}
+	book.ForEachPosition(func(position *Position[*Listing]) {
+		if position.symbol == symbol {
+			memo = position.Memo()
+		}
+	})
+	return memo
+}
+
+func testExec(execID string, execType ExecType, execRefID string, qty, px int64, seconds int) *Report {
+	report := testFill("1", "", "A", Buy, qty, px, seconds)
+	report.ExecID, report.ExecType, report.ExecRefID = execID, execType, execRefID
+	return report
+}
+
+func TestIngesterDuplicates(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A"})
+	book := NewBook("B", whitelist)
+	ingester := NewIngester(book)
+
+	fill := testExec("E1", ExecTypeTrade, "", 10, 100, 1)
+	assert.Nil(t, ingester.Ingest(fill))
+	assert.Nil(t, ingester.Ingest(fill))
+	assert.Nil(t, ingester.Ingest(testExec("E0", ExecTypeNew, "", 0, 0, 0)))
+	assert.Nil(t, ingester.Ingest(testExec("E2", ExecTypeTrade, "", 5, 100, 2)))
+	assert.Equal(t, 1, ingester.Duplicates())
+	assert.True(t, testBookMemo(book, "A").Quantity.Equal(decimal.New(15, 0)))
+
+	//
+	// Without an ExecType a report is a fill if it has a quantity, but
+	// without an ExecID it cannot be de-duplicated.
+	//
+	legacy := testFill("2", "", "A", Sell, 3, 100, 3)
+	assert.Nil(t, ingester.Ingest(legacy))
+	assert.Nil(t, ingester.Ingest(legacy))
+	assert.True(t, testBookMemo(book, "A").Quantity.Equal(decimal.New(9, 0)))
+
+	bad := testExec("E3", ExecTypeTrade, "", 1, 1, 4)
+	bad.Symbol = "Z"
+	assert.NotNil(t, ingester.Ingest(bad))
+
+}
+
+func TestIngesterRefused(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A"})
+	book := NewBook("B", whitelist)
+	ingester := NewIngester(book)
+
+	//
+	// A report that is not a fill does not use up its ExecID.
+	//
+	assert.Nil(t, ingester.Ingest(testExec("E1", ExecTypeNew, "", 0, 0, 0)))
+	assert.Nil(t, ingester.Ingest(testExec("E1", ExecTypeTrade, "", 10, 100, 1)))
+	assert.Equal(t, 0, ingester.Duplicates())
+
+	//
+	// The book refuses a fill in a symbol that is not yet whitelisted, and
+	// takes the resend once it is.
+	//
+	fill := testExec("E2", ExecTypeTrade, "", 5, 50, 2)
+	fill.Symbol = "Z"
+	assert.NotNil(t, ingester.Ingest(fill))
+	whitelist.Add(&Listing{Symbol: "Z"})
+	assert.Nil(t, ingester.Ingest(fill))
+	assert.Equal(t, 0, ingester.Duplicates())
+	assert.True(t, testBookMemo(book, "Z").Quantity.Equal(decimal.New(5, 0)))
+
+	//
+	// Likewise a bust the book refuses.
+	//
+	bust := testExec("E3", ExecTypeTradeCancel, "E2", 0, 0, 3)
+	bust.Symbol = "Z"
+	assert.Nil(t, book.Cancel("Z", "E2")) // Busted behind the ingester's back.
+	assert.NotNil(t, ingester.Ingest(bust))
+	assert.Nil(t, book.TradedWithID("Z", "E2", Buy, decimal.New(5, 0), decimal.New(50, 0)))
+	assert.Nil(t, ingester.Ingest(bust))
+	assert.Equal(t, 0, ingester.Duplicates())
+	assert.True(t, testBookMemo(book, "Z").Quantity.IsZero())
+	assert.Nil(t, ingester.Ingest(bust))
+	assert.Equal(t, 1, ingester.Duplicates())
+
+}
+
+func TestIngesterReorder(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A"})
+	memos := make(chan *PositionMemo, 16)
+	book := NewBook("B", whitelist, WithBookChannel[*Listing](memos))
+	ingester := NewIngester(book, WithReorderWindow[*Listing](2*time.Second))
+
+	//
+	// The second fill arrives first, but within the window.
+	//
+	assert.Nil(t, ingester.Ingest(testExec("E2", ExecTypeTrade, "", 5, 102, 2)))
+	assert.Nil(t, ingester.Ingest(testExec("E1", ExecTypeTrade, "", 10, 101, 1)))
+	assert.Equal(t, 0, len(memos))
+
+	assert.Nil(t, ingester.Ingest(testExec("E3", ExecTypeTrade, "", 1, 103, 3)))
+	assert.Equal(t, 1, len(memos))
+	assert.True(t, (<-memos).Quantity.Equal(decimal.New(10, 0)))
+
+	assert.Nil(t, ingester.Ingest(testExec("E4", ExecTypeTrade, "", 1, 104, 4)))
+	assert.True(t, (<-memos).Quantity.Equal(decimal.New(15, 0)))
+	assert.Equal(t, 0, len(memos))
+
+	assert.Nil(t, ingester.Flush())
+	assert.Equal(t, 2, len(memos))
+	<-memos
+	assert.True(t, (<-memos).Quantity.Equal(decimal.New(17, 0)))
+
+}
+
+func TestIngesterBustAndCorrect(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A"})
+	book := NewBook("B", whitelist)
+	ingester := NewIngester(book)
+
+	assert.Nil(t, ingester.Ingest(testExec("E1", ExecTypeTrade, "", 10, 100, 1)))
+	assert.Nil(t, ingester.Ingest(testExec("E2", ExecTypeTrade, "", 5, 110, 2)))
+
+	//
+	// The bust leaves the position as if the first fill had never happened.
+	//
+	assert.Nil(t, ingester.Ingest(testExec("E3", ExecTypeTradeCancel, "E1", 10, 100, 3)))
+	memo := testBookMemo(book, "A")
+	assert.True(t, memo.Quantity.Equal(decimal.New(5, 0)))
+	assert.True(t, memo.AvgPx.Equal(decimal.New(110, 0)))
+	assert.True(t, memo.Realised.IsZero())
+
+	assert.Nil(t, ingester.Ingest(testExec("E4", ExecTypeTradeCorrect, "E2", 4, 100, 4)))
+	memo = testBookMemo(book, "A")
+	assert.True(t, memo.Quantity.Equal(decimal.New(4, 0)))
+	assert.True(t, memo.AvgPx.Equal(decimal.New(100, 0)))
+
+	//
+	// A second correction amends the corrected fill, and a bust of it
+	// reverses the correction.
+	//
+	assert.Nil(t, ingester.Ingest(testExec("E5", ExecTypeTradeCorrect, "E2", 6, 100, 5)))
+	assert.True(t, testBookMemo(book, "A").Quantity.Equal(decimal.New(6, 0)))
+	assert.Nil(t, ingester.Ingest(testExec("E6", ExecTypeTradeCancel, "E2", 0, 0, 6)))
+	assert.True(t, testBookMemo(book, "A").Quantity.IsZero())
+
+	//
+	// A bust ahead of its fill waits for it.
+	//
+	assert.Nil(t, ingester.Ingest(testExec("E8", ExecTypeTradeCancel, "E7", 3, 100, 8)))
+	assert.Equal(t, 1, len(ingester.Pending()))
+	assert.Nil(t, ingester.Ingest(testExec("E7", ExecTypeTrade, "", 3, 100, 7)))
+	assert.Equal(t, 0, len(ingester.Pending()))
+	assert.True(t, testBookMemo(book, "A").Quantity.IsZero())
+
+}
@@ -0,0 +1,166 @@
+package mkt
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/shopspring/decimal"
+)
+
+// The SecurityType of a listing, FIX field 167. Values are the FIX codes.
+type SecurityType string
+
+// Recognised SecurityType values.
+const (
+	CommonStock SecurityType = SecurityType(enum.SecurityType_COMMON_STOCK)
+	Future      SecurityType = SecurityType(enum.SecurityType_FUTURE)
+	Option      SecurityType = SecurityType(enum.SecurityType_OPTION)
+)
+
+// The SettlMethod of a derivative, FIX field 1193. Values are the FIX codes.
+type SettlMethod string
+
+// Recognised SettlMethod values.
+const (
+	CashSettlement     SettlMethod = SettlMethod(enum.SettlMethod_CASH_SETTLEMENT_REQUIRED)
+	PhysicalSettlement SettlMethod = SettlMethod(enum.SettlMethod_PHYSICAL_SETTLEMENT_REQUIRED)
+)
+
+// PutOrCall of an option, FIX field 201. Unlike FIX, zero is not a put but
+// unrecognised.
+type PutOrCall int64
+
+// Recognised PutOrCall values.
+const (
+	Put  PutOrCall = 1
+	Call PutOrCall = 2
+)
+
+func (x PutOrCall) String() string {
+	switch x {
+	case Put:
+		return "PUT"
+	case Call:
+		return "CALL"
+	default:
+		return ""
+	}
+}
+
+// PutOrCallFromString returns a recognised [PutOrCall] or zero.
+func PutOrCallFromString(s string) PutOrCall {
+	switch s {
+	case "PUT":
+		return Put
+	case "CALL":
+		return Call
+	default:
+		return 0
+	}
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (x PutOrCall) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.String())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (x *PutOrCall) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*x = PutOrCallFromString(s)
+	return nil
+}
+
+// AsQuickFIX returns this [PutOrCall] as a QuickFIX field. An unrecognised
+// value is returned as a put.
+func (x PutOrCall) AsQuickFIX() field.PutOrCallField {
+	if x == Call {
+		return field.NewPutOrCall(enum.PutOrCall_CALL)
+	}
+	return field.NewPutOrCall(enum.PutOrCall_PUT)
+}
+
+// PutOrCallFromFIX returns the equivalent [PutOrCall] from the QuickFIX
+// field, or zero if there is no equivalence.
+func PutOrCallFromFIX(putOrCall field.PutOrCallField) PutOrCall {
+	switch putOrCall.Value() {
+	case enum.PutOrCall_PUT:
+		return Put
+	case enum.PutOrCall_CALL:
+		return Call
+	default:
+		return 0
+	}
+}
+
+// Instrument holds the optional details of a derivative [Listing]. Fields
+// which do not apply, such as the strike of a future, are left zero.
+type Instrument struct {
+	SecurityType SecurityType    `json:",omitempty"` // FIX field 167
+	Expiry       time.Time       // FIX field 541, with the time of expiry
+	Underlying   string          `json:",omitempty"` // FIX field 311
+	PutOrCall    PutOrCall       `json:",omitempty"` // FIX field 201
+	StrikePrice  decimal.Decimal // FIX field 202
+	SettlMethod  SettlMethod     `json:",omitempty"` // FIX field 1193
+}
+
+// Expired returns true if there is an expiry and the time is at or after it.
+func (x *Instrument) Expired(t time.Time) bool {
+	return !x.Expiry.IsZero() && !t.Before(x.Expiry)
+}
+
+// DaysToExpiry returns the number of calendar days from the date of the time
+// to the date of expiry, both in the time zone of the expiry. It is zero on
+// the day of expiry and negative afterwards.
+func (x *Instrument) DaysToExpiry(t time.Time) int {
+	ey, em, ed := x.Expiry.Date()
+	ty, tm, td := t.In(x.Expiry.Location()).Date()
+	expiry := time.Date(ey, em, ed, 0, 0, 0, 0, time.UTC)
+	today := time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC)
+	return int(expiry.Sub(today) / (24 * time.Hour))
+}
+
+// Expired returns true if the listing has [Instrument] details with an
+// expiry at or before the time.
+func (x *Listing) Expired(t time.Time) bool {
+	return x.Instrument != nil && x.Instrument.Expired(t)
+}
+
+// Expired returns the whitelisted listings which have expired at the time,
+// in symbol order, so they can be removed.
+func (x *WhiteList[T]) Expired(t time.Time) []T {
+	var expired []T
+	x.ForEach(func(listing T) {
+		if listing.Definition().Expired(t) {
+			expired = append(expired, listing)
+		}
+	})
+	return expired
+}
+
+// Futures returns the whitelisted futures on the underlying which have not
+// expired at the time, nearest expiry first. This is the sequence of
+// contracts to roll through.
+func (x *WhiteList[T]) Futures(underlying string, t time.Time) []T {
+	var futures []T
+	x.ForEach(func(listing T) {
+		def := listing.Definition()
+		if def.Instrument == nil || def.Instrument.SecurityType != Future || def.Instrument.Underlying != underlying {
+			return
+		}
+		if def.Instrument.Expired(t) {
+			return
+		}
+		futures = append(futures, listing)
+	})
+	sort.SliceStable(futures, func(i, j int) bool {
+		return futures[i].Definition().Instrument.Expiry.Before(futures[j].Definition().Instrument.Expiry)
+	})
+	return futures
+}
@@ -0,0 +1,94 @@
+package mkt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFuture(symbol string, expiry time.Time) *Listing {
+	return &Listing{
+		Symbol:             symbol,
+		TickIncrement:      decimal.New(25, -2),
+		RoundLot:           DecimalOne,
+		ContractMultiplier: decimal.New(50, 0),
+		Instrument: &Instrument{
+			SecurityType: Future,
+			Expiry:       expiry,
+			Underlying:   "SPX",
+			SettlMethod:  CashSettlement,
+		},
+	}
+}
+
+func TestInstrumentExpiry(t *testing.T) {
+
+	expiry := time.Date(2026, 12, 18, 14, 30, 0, 0, time.UTC)
+	instrument := &Instrument{SecurityType: Future, Expiry: expiry}
+
+	assert.Equal(t, 3, instrument.DaysToExpiry(time.Date(2026, 12, 15, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, instrument.DaysToExpiry(expiry.Add(time.Hour)))
+	assert.Equal(t, -1, instrument.DaysToExpiry(expiry.Add(24*time.Hour)))
+
+	assert.False(t, instrument.Expired(expiry.Add(-time.Second)))
+	assert.True(t, instrument.Expired(expiry))
+
+	assert.False(t, (&Listing{}).Expired(expiry), "no instrument")
+	assert.False(t, (&Listing{Instrument: &Instrument{}}).Expired(expiry), "no expiry")
+
+}
+
+func TestInstrumentJSON(t *testing.T) {
+
+	listing := &Listing{
+		Symbol: "SPX 261218C5000",
+		Instrument: &Instrument{
+			SecurityType: Option,
+			Expiry:       time.Date(2026, 12, 18, 14, 30, 0, 0, time.UTC),
+			Underlying:   "SPX",
+			PutOrCall:    Call,
+			StrikePrice:  decimal.New(5000, 0),
+			SettlMethod:  CashSettlement,
+		},
+	}
+
+	b, err := json.Marshal(listing)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"PutOrCall":"CALL"`)
+	assert.Contains(t, string(b), `"SecurityType":"OPT"`)
+
+	var decoded Listing
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, Call, decoded.Instrument.PutOrCall)
+	assert.True(t, decoded.Instrument.StrikePrice.Equal(decimal.New(5000, 0)))
+	assert.True(t, decoded.Instrument.Expiry.Equal(listing.Instrument.Expiry))
+
+	assert.Equal(t, Call, PutOrCallFromFIX(Call.AsQuickFIX()))
+	assert.Equal(t, Put, PutOrCallFromFIX(Put.AsQuickFIX()))
+
+}
+
+func TestWhiteListFutures(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(testFuture("ESZ6", time.Date(2026, 12, 18, 14, 30, 0, 0, time.UTC)))
+	whitelist.Add(testFuture("ESH7", time.Date(2027, 3, 19, 14, 30, 0, 0, time.UTC)))
+	whitelist.Add(testFuture("ESU6", time.Date(2026, 9, 18, 14, 30, 0, 0, time.UTC)))
+	whitelist.Add(&Listing{Symbol: "A"})
+
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	futures := whitelist.Futures("SPX", now)
+	assert.Equal(t, 2, len(futures))
+	assert.Equal(t, "ESZ6", futures[0].Symbol)
+	assert.Equal(t, "ESH7", futures[1].Symbol)
+	assert.Equal(t, 0, len(whitelist.Futures("NDX", now)))
+
+	expired := whitelist.Expired(now)
+	assert.Equal(t, 1, len(expired))
+	assert.Equal(t, "ESU6", expired[0].Symbol)
+
+}
@@ -9,12 +9,19 @@ import (
 //
 // Listing is principally a data object and stateless, so its fields are
 // exported for convenience.
+//
+// If the TickTable is set it governs the tick increment at each price,
+// otherwise the TickIncrement applies at every price. The Calendar, if set,
+// is the trading hours and may be shared by all listings on a venue.
 type Listing struct {
 	Symbol             string          // FIX field 55
 	TickIncrement      decimal.Decimal // FIX field 1208
 	RoundLot           decimal.Decimal // FIX field 561
 	MinTradeVol        decimal.Decimal // FIX field 562
 	ContractMultiplier decimal.Decimal // FIX field 231
+	TickTable          *TickTable      `json:",omitempty"` // FIX component TickRules, optional
+	Calendar           *Calendar       `json:"-"`          // Optional
+	Instrument         *Instrument     `json:",omitempty"` // Optional derivative details
 }
 
 // Definition returns the [*Listing].
@@ -24,3 +31,87 @@ func (x *Listing) Definition() *Listing { return x }
 type AnyListing interface {
 	Definition() *Listing
 }
+
+// TickAt returns the tick increment at the price.
+func (x *Listing) TickAt(price decimal.Decimal) decimal.Decimal {
+	if x.TickTable != nil {
+		return x.TickTable.Increment(price)
+	}
+	return x.TickIncrement
+}
+
+// RoundPrice returns the price rounded down to a whole tick. If there is no
+// tick increment the price is returned unchanged.
+func (x *Listing) RoundPrice(price decimal.Decimal) decimal.Decimal {
+	if x.TickTable != nil {
+		return x.TickTable.Round(price)
+	}
+	if !x.TickIncrement.IsPositive() {
+		return price
+	}
+	return price.Sub(floorMod(price, x.TickIncrement))
+}
+
+// roundPriceUp returns the price rounded up to a whole tick.
+func (x *Listing) roundPriceUp(price decimal.Decimal) decimal.Decimal {
+	if x.TickTable != nil {
+		return x.TickTable.RoundUp(price)
+	}
+	rounded := x.RoundPrice(price)
+	if rounded.Equal(price) {
+		return price
+	}
+	return rounded.Add(x.TickIncrement)
+}
+
+// TickUp returns the price moved up by n ticks, after rounding down to a whole
+// tick.
+func (x *Listing) TickUp(price decimal.Decimal, n int64) decimal.Decimal {
+	if x.TickTable != nil {
+		return x.TickTable.Up(price, n)
+	}
+	if !x.TickIncrement.IsPositive() {
+		return price
+	}
+	if n < 0 {
+		return x.TickDown(price, -n)
+	}
+	return x.RoundPrice(price).Add(x.TickIncrement.Mul(decimal.New(n, 0)))
+}
+
+// TickDown returns the price moved down by n ticks, after rounding up to a
+// whole tick.
+func (x *Listing) TickDown(price decimal.Decimal, n int64) decimal.Decimal {
+	if x.TickTable != nil {
+		return x.TickTable.Down(price, n)
+	}
+	if !x.TickIncrement.IsPositive() {
+		return price
+	}
+	if n < 0 {
+		return x.TickUp(price, -n)
+	}
+	return x.roundPriceUp(price).Sub(x.TickIncrement.Mul(decimal.New(n, 0)))
+}
+
+// Ticks returns the signed number of ticks from one price to another.
+func (x *Listing) Ticks(from, to decimal.Decimal) int64 {
+	if x.TickTable != nil {
+		return x.TickTable.Ticks(from, to)
+	}
+	if !x.TickIncrement.IsPositive() {
+		return 0
+	}
+	if from.GreaterThan(to) {
+		return -x.Ticks(to, from)
+	}
+	return to.Sub(from).Div(x.TickIncrement).Ceil().IntPart()
+}
+
+// PricePrecision returns the number of decimals in the finest tick increment.
+func (x *Listing) PricePrecision() int32 {
+	if x.TickTable != nil {
+		return x.TickTable.Precision()
+	}
+	return Precision(x.TickIncrement)
+}
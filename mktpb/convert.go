@@ -0,0 +1,370 @@
+package mktpb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gbkr-com/mkt"
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SideToProto returns the [Side] for the [mkt.Side].
+func SideToProto(side mkt.Side) Side {
+	switch side {
+	case mkt.Buy:
+		return Side_SIDE_BUY
+	case mkt.Sell:
+		return Side_SIDE_SELL
+	default:
+		return Side_SIDE_UNSPECIFIED
+	}
+}
+
+// SideFromProto returns the [mkt.Side] for the [Side], or zero.
+func SideFromProto(side Side) mkt.Side {
+	switch side {
+	case Side_SIDE_BUY:
+		return mkt.Buy
+	case Side_SIDE_SELL:
+		return mkt.Sell
+	default:
+		return 0
+	}
+}
+
+// OrdStatusToProto returns the [OrdStatus] for the [mkt.OrdStatus]. The two
+// are numbered alike.
+func OrdStatusToProto(ordStatus mkt.OrdStatus) OrdStatus {
+	if _, ok := OrdStatus_name[int32(ordStatus)]; !ok {
+		return OrdStatus_ORD_STATUS_UNSPECIFIED
+	}
+	return OrdStatus(ordStatus)
+}
+
+// OrdStatusFromProto returns the [mkt.OrdStatus] for the [OrdStatus], or
+// zero.
+func OrdStatusFromProto(ordStatus OrdStatus) mkt.OrdStatus {
+	if _, ok := OrdStatus_name[int32(ordStatus)]; !ok {
+		return 0
+	}
+	return mkt.OrdStatus(ordStatus)
+}
+
+// ExecTypeToProto returns the [ExecType] for the [mkt.ExecType]. The two are
+// numbered alike.
+func ExecTypeToProto(execType mkt.ExecType) ExecType {
+	if _, ok := ExecType_name[int32(execType)]; !ok {
+		return ExecType_EXEC_TYPE_UNSPECIFIED
+	}
+	return ExecType(execType)
+}
+
+// ExecTypeFromProto returns the [mkt.ExecType] for the [ExecType], or zero.
+func ExecTypeFromProto(execType ExecType) mkt.ExecType {
+	if _, ok := ExecType_name[int32(execType)]; !ok {
+		return 0
+	}
+	return mkt.ExecType(execType)
+}
+
+// TimeInForceToProto returns the [TimeInForce] for the [mkt.TimeInForce].
+func TimeInForceToProto(timeInForce mkt.TimeInForce) TimeInForce {
+	switch timeInForce {
+	case mkt.GTC:
+		return TimeInForce_TIME_IN_FORCE_GTC
+	case mkt.IOC:
+		return TimeInForce_TIME_IN_FORCE_IOC
+	default:
+		return TimeInForce_TIME_IN_FORCE_UNSPECIFIED
+	}
+}
+
+// TimeInForceFromProto returns the [mkt.TimeInForce] for the [TimeInForce],
+// or zero.
+func TimeInForceFromProto(timeInForce TimeInForce) mkt.TimeInForce {
+	switch timeInForce {
+	case TimeInForce_TIME_IN_FORCE_GTC:
+		return mkt.GTC
+	case TimeInForce_TIME_IN_FORCE_IOC:
+		return mkt.IOC
+	default:
+		return 0
+	}
+}
+
+// MsgTypeToProto returns the [MsgType] for the [mkt.MsgType].
+func MsgTypeToProto(msgType mkt.MsgType) MsgType {
+	switch msgType {
+	case mkt.OrderNew:
+		return MsgType_MSG_TYPE_NEW
+	case mkt.OrderCancel:
+		return MsgType_MSG_TYPE_CANCEL
+	case mkt.OrderReplace:
+		return MsgType_MSG_TYPE_REPLACE
+	default:
+		return MsgType_MSG_TYPE_UNSPECIFIED
+	}
+}
+
+// MsgTypeFromProto returns the [mkt.MsgType] for the [MsgType], or zero.
+func MsgTypeFromProto(msgType MsgType) mkt.MsgType {
+	switch msgType {
+	case MsgType_MSG_TYPE_NEW:
+		return mkt.OrderNew
+	case MsgType_MSG_TYPE_CANCEL:
+		return mkt.OrderCancel
+	case MsgType_MSG_TYPE_REPLACE:
+		return mkt.OrderReplace
+	default:
+		return 0
+	}
+}
+
+// OrderToProto returns the [*Order] for the [*mkt.Order].
+func OrderToProto(order *mkt.Order) *Order {
+	return &Order{
+		MsgType: MsgTypeToProto(order.MsgType),
+		OrderId: order.OrderID,
+		Side:    SideToProto(order.Side),
+		Symbol:  order.Symbol,
+	}
+}
+
+// OrderFromProto returns the [*mkt.Order] for the [*Order].
+func OrderFromProto(order *Order) *mkt.Order {
+	return &mkt.Order{
+		MsgType: MsgTypeFromProto(order.GetMsgType()),
+		OrderID: order.GetOrderId(),
+		Side:    SideFromProto(order.GetSide()),
+		Symbol:  order.GetSymbol(),
+	}
+}
+
+// ReportToProto returns the [*Report] for the [*mkt.Report].
+func ReportToProto(report *mkt.Report) *Report {
+	return &Report{
+		OrderId:          report.OrderID,
+		Symbol:           report.Symbol,
+		Side:             SideToProto(report.Side),
+		SecondaryOrderId: report.SecondaryOrderID,
+		ClOrdId:          report.ClOrdID,
+		OrdStatus:        OrdStatusToProto(report.OrdStatus),
+		Account:          report.Account,
+		TimeInForce:      TimeInForceToProto(report.TimeInForce),
+		LastQty:          report.LastQty.String(),
+		LastPx:           report.LastPx.String(),
+		TransactTime:     timestampToProto(report.TransactTime),
+		ExecInst:         report.ExecInst,
+		ExecId:           report.ExecID,
+		ExecType:         ExecTypeToProto(report.ExecType),
+		ExecRefId:        report.ExecRefID,
+	}
+}
+
+// ReportFromProto returns the [*mkt.Report] for the [*Report], or an error if
+// a decimal cannot be parsed.
+func ReportFromProto(report *Report) (*mkt.Report, error) {
+	var p parser
+	x := &mkt.Report{
+		OrderID:          report.GetOrderId(),
+		Symbol:           report.GetSymbol(),
+		Side:             SideFromProto(report.GetSide()),
+		SecondaryOrderID: report.GetSecondaryOrderId(),
+		ClOrdID:          report.GetClOrdId(),
+		OrdStatus:        OrdStatusFromProto(report.GetOrdStatus()),
+		Account:          report.GetAccount(),
+		TimeInForce:      TimeInForceFromProto(report.GetTimeInForce()),
+		LastQty:          p.decimal("last_qty", report.GetLastQty()),
+		LastPx:           p.decimal("last_px", report.GetLastPx()),
+		TransactTime:     timestampFromProto(report.GetTransactTime()),
+		ExecInst:         report.GetExecInst(),
+		ExecID:           report.GetExecId(),
+		ExecType:         ExecTypeFromProto(report.GetExecType()),
+		ExecRefID:        report.GetExecRefId(),
+	}
+	return x, p.result("mktpb.ReportFromProto")
+}
+
+// QuoteToProto returns the [*Quote] for the [*mkt.Quote].
+func QuoteToProto(quote *mkt.Quote) *Quote {
+	return &Quote{
+		Symbol:  quote.Symbol,
+		BidPx:   quote.BidPx.String(),
+		BidSize: quote.BidSize.String(),
+		AskPx:   quote.AskPx.String(),
+		AskSize: quote.AskSize.String(),
+	}
+}
+
+// QuoteFromProto returns the [*mkt.Quote] for the [*Quote], or an error if a
+// decimal cannot be parsed.
+func QuoteFromProto(quote *Quote) (*mkt.Quote, error) {
+	var p parser
+	x := &mkt.Quote{
+		Symbol:  quote.GetSymbol(),
+		BidPx:   p.decimal("bid_px", quote.GetBidPx()),
+		BidSize: p.decimal("bid_size", quote.GetBidSize()),
+		AskPx:   p.decimal("ask_px", quote.GetAskPx()),
+		AskSize: p.decimal("ask_size", quote.GetAskSize()),
+	}
+	return x, p.result("mktpb.QuoteFromProto")
+}
+
+// TradeToProto returns the [*Trade] for the [*mkt.Trade].
+func TradeToProto(trade *mkt.Trade) *Trade {
+	return &Trade{
+		Symbol:      trade.Symbol,
+		LastQty:     trade.LastQty.String(),
+		LastPx:      trade.LastPx.String(),
+		TradeVolume: trade.TradeVolume.String(),
+		AvgPx:       trade.AvgPx.String(),
+	}
+}
+
+// TradeFromProto returns the [*mkt.Trade] for the [*Trade], or an error if a
+// decimal cannot be parsed.
+func TradeFromProto(trade *Trade) (*mkt.Trade, error) {
+	var p parser
+	x := &mkt.Trade{
+		Symbol:      trade.GetSymbol(),
+		LastQty:     p.decimal("last_qty", trade.GetLastQty()),
+		LastPx:      p.decimal("last_px", trade.GetLastPx()),
+		TradeVolume: p.decimal("trade_volume", trade.GetTradeVolume()),
+		AvgPx:       p.decimal("avg_px", trade.GetAvgPx()),
+	}
+	return x, p.result("mktpb.TradeFromProto")
+}
+
+// PositionMemoToProto returns the [*PositionMemo] for the [*mkt.PositionMemo].
+func PositionMemoToProto(memo *mkt.PositionMemo) *PositionMemo {
+	return &PositionMemo{
+		Symbol:   memo.Symbol,
+		Quantity: memo.Quantity.String(),
+		AvgPx:    memo.AvgPx.String(),
+		Realised: memo.Realised.String(),
+	}
+}
+
+// PositionMemoFromProto returns the [*mkt.PositionMemo] for the
+// [*PositionMemo], or an error if a decimal cannot be parsed.
+func PositionMemoFromProto(memo *PositionMemo) (*mkt.PositionMemo, error) {
+	var p parser
+	x := &mkt.PositionMemo{
+		Symbol:   memo.GetSymbol(),
+		Quantity: p.decimal("quantity", memo.GetQuantity()),
+		AvgPx:    p.decimal("avg_px", memo.GetAvgPx()),
+		Realised: p.decimal("realised", memo.GetRealised()),
+	}
+	return x, p.result("mktpb.PositionMemoFromProto")
+}
+
+// ListingToProto returns the [*Listing] for the [*mkt.Listing]. The calendar
+// is not carried.
+func ListingToProto(listing *mkt.Listing) *Listing {
+	x := &Listing{
+		Symbol:             listing.Symbol,
+		TickIncrement:      listing.TickIncrement.String(),
+		RoundLot:           listing.RoundLot.String(),
+		MinTradeVol:        listing.MinTradeVol.String(),
+		ContractMultiplier: listing.ContractMultiplier.String(),
+	}
+	if listing.TickTable != nil {
+		for _, band := range listing.TickTable.Bands() {
+			x.TickTable = append(x.TickTable, &TickBand{From: band.From.String(), Increment: band.Increment.String()})
+		}
+	}
+	if instrument := listing.Instrument; instrument != nil {
+		x.Instrument = &Instrument{
+			SecurityType: string(instrument.SecurityType),
+			Expiry:       timestampToProto(instrument.Expiry),
+			Underlying:   instrument.Underlying,
+			PutOrCall:    PutOrCall(instrument.PutOrCall),
+			StrikePrice:  instrument.StrikePrice.String(),
+			SettlMethod:  string(instrument.SettlMethod),
+		}
+	}
+	return x
+}
+
+// ListingFromProto returns the [*mkt.Listing] for the [*Listing], or an
+// error if a decimal cannot be parsed or the tick table is not valid.
+func ListingFromProto(listing *Listing) (*mkt.Listing, error) {
+
+	var p parser
+	x := &mkt.Listing{
+		Symbol:             listing.GetSymbol(),
+		TickIncrement:      p.decimal("tick_increment", listing.GetTickIncrement()),
+		RoundLot:           p.decimal("round_lot", listing.GetRoundLot()),
+		MinTradeVol:        p.decimal("min_trade_vol", listing.GetMinTradeVol()),
+		ContractMultiplier: p.decimal("contract_multiplier", listing.GetContractMultiplier()),
+	}
+
+	if len(listing.GetTickTable()) > 0 {
+		bands := make([]mkt.TickBand, 0, len(listing.GetTickTable()))
+		for _, band := range listing.GetTickTable() {
+			bands = append(bands, mkt.TickBand{
+				From:      p.decimal("tick_table.from", band.GetFrom()),
+				Increment: p.decimal("tick_table.increment", band.GetIncrement()),
+			})
+		}
+		if p.err == nil {
+			x.TickTable, p.err = mkt.NewTickTable(bands...)
+		}
+	}
+
+	if instrument := listing.GetInstrument(); instrument != nil {
+		x.Instrument = &mkt.Instrument{
+			SecurityType: mkt.SecurityType(instrument.GetSecurityType()),
+			Expiry:       timestampFromProto(instrument.GetExpiry()),
+			Underlying:   instrument.GetUnderlying(),
+			PutOrCall:    mkt.PutOrCall(instrument.GetPutOrCall()),
+			StrikePrice:  p.decimal("instrument.strike_price", instrument.GetStrikePrice()),
+			SettlMethod:  mkt.SettlMethod(instrument.GetSettlMethod()),
+		}
+	}
+
+	return x, p.result("mktpb.ListingFromProto")
+
+}
+
+// parser parses decimal fields, keeping the first error.
+type parser struct {
+	err error
+}
+
+// decimal parses the value, where an empty string is zero.
+func (x *parser) decimal(name, value string) decimal.Decimal {
+	if value == "" || x.err != nil {
+		return decimal.Zero
+	}
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		x.err = fmt.Errorf("%s: %w", name, err)
+		return decimal.Zero
+	}
+	return d
+}
+
+func (x *parser) result(prefix string) error {
+	if x.err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", prefix, x.err)
+}
+
+// timestampToProto returns nil for the zero time.
+func timestampToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// timestampFromProto returns the zero time for nil.
+func timestampFromProto(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime()
+}
@@ -0,0 +1,156 @@
+package mktpb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gbkr-com/mkt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// roundTrip marshals and unmarshals the message, as if it crossed a service
+// boundary.
+func roundTrip[M proto.Message](t *testing.T, message M, into M) M {
+	b, err := proto.Marshal(message)
+	assert.Nil(t, err)
+	assert.Nil(t, proto.Unmarshal(b, into))
+	return into
+}
+
+func TestEnums(t *testing.T) {
+
+	for _, side := range []mkt.Side{mkt.Buy, mkt.Sell} {
+		assert.Equal(t, side, SideFromProto(SideToProto(side)))
+	}
+	for ordStatus := mkt.OrdStatusNew; ordStatus <= mkt.OrdStatusPendingReplace; ordStatus++ {
+		assert.Equal(t, ordStatus, OrdStatusFromProto(OrdStatusToProto(ordStatus)))
+	}
+	for execType := mkt.ExecTypeNew; execType <= mkt.ExecTypePendingReplace; execType++ {
+		assert.Equal(t, execType, ExecTypeFromProto(ExecTypeToProto(execType)))
+	}
+	for _, timeInForce := range []mkt.TimeInForce{mkt.GTC, mkt.IOC} {
+		assert.Equal(t, timeInForce, TimeInForceFromProto(TimeInForceToProto(timeInForce)))
+	}
+	for _, msgType := range []mkt.MsgType{mkt.OrderNew, mkt.OrderCancel, mkt.OrderReplace} {
+		assert.Equal(t, msgType, MsgTypeFromProto(MsgTypeToProto(msgType)))
+	}
+
+	assert.Equal(t, Side_SIDE_UNSPECIFIED, SideToProto(0))
+	assert.Equal(t, OrdStatus_ORD_STATUS_UNSPECIFIED, OrdStatusToProto(42))
+	assert.Equal(t, mkt.OrdStatus(0), OrdStatusFromProto(42))
+	assert.Equal(t, ExecType_EXEC_TYPE_UNSPECIFIED, ExecTypeToProto(42))
+
+}
+
+func TestOrderAndReport(t *testing.T) {
+
+	order := &mkt.Order{MsgType: mkt.OrderReplace, OrderID: "O1", Side: mkt.Sell, Symbol: "A"}
+	assert.Equal(t, order, OrderFromProto(roundTrip(t, OrderToProto(order), &Order{})))
+
+	report := &mkt.Report{
+		OrderID:          "O1",
+		Symbol:           "A",
+		Side:             mkt.Buy,
+		SecondaryOrderID: "S1",
+		ClOrdID:          "C1",
+		OrdStatus:        mkt.OrdStatusPartiallyFilled,
+		Account:          "ACC",
+		TimeInForce:      mkt.IOC,
+		LastQty:          decimal.New(10, 0),
+		LastPx:           decimal.New(4215, -2),
+		TransactTime:     time.Date(2026, 10, 19, 9, 30, 0, 123, time.UTC),
+		ExecInst:         "e",
+		ExecID:           "E2",
+		ExecType:         mkt.ExecTypeTradeCorrect,
+		ExecRefID:        "E1",
+	}
+	decoded, err := ReportFromProto(roundTrip(t, ReportToProto(report), &Report{}))
+	assert.Nil(t, err)
+	assert.True(t, report.LastPx.Equal(decoded.LastPx))
+	assert.True(t, report.TransactTime.Equal(decoded.TransactTime))
+	decoded.LastQty, decoded.LastPx = report.LastQty, report.LastPx
+	decoded.TransactTime = report.TransactTime
+	assert.Equal(t, report, decoded)
+
+	decoded, err = ReportFromProto(&Report{OrderId: "O2"})
+	assert.Nil(t, err)
+	assert.True(t, decoded.TransactTime.IsZero())
+	assert.True(t, decoded.LastQty.IsZero())
+
+	_, err = ReportFromProto(&Report{LastPx: "x"})
+	assert.ErrorContains(t, err, "last_px")
+
+}
+
+func TestMarketData(t *testing.T) {
+
+	quote := &mkt.Quote{Symbol: "A", BidPx: decimal.New(4215, -2), BidSize: decimal.New(100, 0), AskPx: decimal.New(4216, -2), AskSize: decimal.New(5, 1)}
+	decodedQuote, err := QuoteFromProto(roundTrip(t, QuoteToProto(quote), &Quote{}))
+	assert.Nil(t, err)
+	assert.True(t, quote.AskSize.Equal(decodedQuote.AskSize))
+	assert.True(t, quote.BidPx.Equal(decodedQuote.BidPx))
+
+	trade := &mkt.Trade{Symbol: "A", LastQty: decimal.New(10, 0), LastPx: decimal.New(4216, -2), TradeVolume: decimal.New(30, 0), AvgPx: decimal.New(421567, -4)}
+	decodedTrade, err := TradeFromProto(roundTrip(t, TradeToProto(trade), &Trade{}))
+	assert.Nil(t, err)
+	assert.True(t, trade.AvgPx.Equal(decodedTrade.AvgPx))
+	assert.True(t, trade.TradeVolume.Equal(decodedTrade.TradeVolume))
+
+	memo := &mkt.PositionMemo{Symbol: "A", Quantity: decimal.New(-10, 0), AvgPx: decimal.New(42155, -3), Realised: decimal.New(-125, -1)}
+	decodedMemo, err := PositionMemoFromProto(roundTrip(t, PositionMemoToProto(memo), &PositionMemo{}))
+	assert.Nil(t, err)
+	assert.True(t, memo.Quantity.Equal(decodedMemo.Quantity))
+	assert.True(t, memo.Realised.Equal(decodedMemo.Realised))
+
+	_, err = QuoteFromProto(&Quote{BidSize: "1..0"})
+	assert.ErrorContains(t, err, "bid_size")
+
+}
+
+func TestListing(t *testing.T) {
+
+	table, err := mkt.NewTickTable(
+		mkt.TickBand{From: decimal.Zero, Increment: decimal.New(1, -2)},
+		mkt.TickBand{From: decimal.New(10, 0), Increment: decimal.New(5, -2)},
+	)
+	assert.Nil(t, err)
+
+	listing := &mkt.Listing{
+		Symbol:             "SPX C5000",
+		TickIncrement:      decimal.New(5, -2),
+		RoundLot:           mkt.DecimalOne,
+		MinTradeVol:        mkt.DecimalOne,
+		ContractMultiplier: decimal.New(100, 0),
+		TickTable:          table,
+		Instrument: &mkt.Instrument{
+			SecurityType: mkt.Option,
+			Expiry:       time.Date(2026, 12, 18, 14, 30, 0, 0, time.UTC),
+			Underlying:   "SPX",
+			PutOrCall:    mkt.Call,
+			StrikePrice:  decimal.New(5000, 0),
+			SettlMethod:  mkt.CashSettlement,
+		},
+	}
+
+	decoded, err := ListingFromProto(roundTrip(t, ListingToProto(listing), &Listing{}))
+	assert.Nil(t, err)
+	assert.Equal(t, listing.Symbol, decoded.Symbol)
+	assert.True(t, listing.ContractMultiplier.Equal(decoded.ContractMultiplier))
+	assert.Equal(t, 2, len(decoded.TickTable.Bands()))
+	assert.True(t, decoded.TickAt(decimal.New(11, 0)).Equal(decimal.New(5, -2)))
+	assert.Equal(t, mkt.Call, decoded.Instrument.PutOrCall)
+	assert.Equal(t, mkt.Option, decoded.Instrument.SecurityType)
+	assert.True(t, listing.Instrument.Expiry.Equal(decoded.Instrument.Expiry))
+	assert.True(t, listing.Instrument.StrikePrice.Equal(decoded.Instrument.StrikePrice))
+
+	decoded, err = ListingFromProto(&Listing{Symbol: "A"})
+	assert.Nil(t, err)
+	assert.Nil(t, decoded.TickTable)
+	assert.Nil(t, decoded.Instrument)
+
+	_, err = ListingFromProto(&Listing{TickTable: []*TickBand{{From: "0", Increment: "0"}}})
+	assert.NotNil(t, err)
+
+}
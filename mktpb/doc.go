@@ -0,0 +1,4 @@
+// Package mktpb holds the Protocol Buffers definitions of the mkt types, in
+// mkt.proto, and conversions to and from them so the types can cross service
+// boundaries. Decimals are carried as strings.
+package mktpb
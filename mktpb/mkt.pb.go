@@ -0,0 +1,1227 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: mkt.proto
+
+package mktpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Side int32
+
+const (
+	Side_SIDE_UNSPECIFIED Side = 0
+	Side_SIDE_BUY         Side = 1
+	Side_SIDE_SELL        Side = 2
+)
+
+// Enum value maps for Side.
+var (
+	Side_name = map[int32]string{
+		0: "SIDE_UNSPECIFIED",
+		1: "SIDE_BUY",
+		2: "SIDE_SELL",
+	}
+	Side_value = map[string]int32{
+		"SIDE_UNSPECIFIED": 0,
+		"SIDE_BUY":         1,
+		"SIDE_SELL":        2,
+	}
+)
+
+func (x Side) Enum() *Side {
+	p := new(Side)
+	*p = x
+	return p
+}
+
+func (x Side) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Side) Descriptor() protoreflect.EnumDescriptor {
+	return file_mkt_proto_enumTypes[0].Descriptor()
+}
+
+func (Side) Type() protoreflect.EnumType {
+	return &file_mkt_proto_enumTypes[0]
+}
+
+func (x Side) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Side.Descriptor instead.
+func (Side) EnumDescriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{0}
+}
+
+type OrdStatus int32
+
+const (
+	OrdStatus_ORD_STATUS_UNSPECIFIED      OrdStatus = 0
+	OrdStatus_ORD_STATUS_NEW              OrdStatus = 1
+	OrdStatus_ORD_STATUS_PARTIALLY_FILLED OrdStatus = 2
+	OrdStatus_ORD_STATUS_FILLED           OrdStatus = 3
+	OrdStatus_ORD_STATUS_CANCELED         OrdStatus = 4
+	OrdStatus_ORD_STATUS_PENDING_CANCEL   OrdStatus = 5
+	OrdStatus_ORD_STATUS_REJECTED         OrdStatus = 6
+	OrdStatus_ORD_STATUS_PENDING_NEW      OrdStatus = 7
+	OrdStatus_ORD_STATUS_EXPIRED          OrdStatus = 8
+	OrdStatus_ORD_STATUS_PENDING_REPLACE  OrdStatus = 9
+)
+
+// Enum value maps for OrdStatus.
+var (
+	OrdStatus_name = map[int32]string{
+		0: "ORD_STATUS_UNSPECIFIED",
+		1: "ORD_STATUS_NEW",
+		2: "ORD_STATUS_PARTIALLY_FILLED",
+		3: "ORD_STATUS_FILLED",
+		4: "ORD_STATUS_CANCELED",
+		5: "ORD_STATUS_PENDING_CANCEL",
+		6: "ORD_STATUS_REJECTED",
+		7: "ORD_STATUS_PENDING_NEW",
+		8: "ORD_STATUS_EXPIRED",
+		9: "ORD_STATUS_PENDING_REPLACE",
+	}
+	OrdStatus_value = map[string]int32{
+		"ORD_STATUS_UNSPECIFIED":      0,
+		"ORD_STATUS_NEW":              1,
+		"ORD_STATUS_PARTIALLY_FILLED": 2,
+		"ORD_STATUS_FILLED":           3,
+		"ORD_STATUS_CANCELED":         4,
+		"ORD_STATUS_PENDING_CANCEL":   5,
+		"ORD_STATUS_REJECTED":         6,
+		"ORD_STATUS_PENDING_NEW":      7,
+		"ORD_STATUS_EXPIRED":          8,
+		"ORD_STATUS_PENDING_REPLACE":  9,
+	}
+)
+
+func (x OrdStatus) Enum() *OrdStatus {
+	p := new(OrdStatus)
+	*p = x
+	return p
+}
+
+func (x OrdStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrdStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_mkt_proto_enumTypes[1].Descriptor()
+}
+
+func (OrdStatus) Type() protoreflect.EnumType {
+	return &file_mkt_proto_enumTypes[1]
+}
+
+func (x OrdStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrdStatus.Descriptor instead.
+func (OrdStatus) EnumDescriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{1}
+}
+
+type ExecType int32
+
+const (
+	ExecType_EXEC_TYPE_UNSPECIFIED     ExecType = 0
+	ExecType_EXEC_TYPE_NEW             ExecType = 1
+	ExecType_EXEC_TYPE_TRADE           ExecType = 2
+	ExecType_EXEC_TYPE_TRADE_CANCEL    ExecType = 3
+	ExecType_EXEC_TYPE_TRADE_CORRECT   ExecType = 4
+	ExecType_EXEC_TYPE_CANCELED        ExecType = 5
+	ExecType_EXEC_TYPE_REPLACED        ExecType = 6
+	ExecType_EXEC_TYPE_REJECTED        ExecType = 7
+	ExecType_EXEC_TYPE_EXPIRED         ExecType = 8
+	ExecType_EXEC_TYPE_PENDING_NEW     ExecType = 9
+	ExecType_EXEC_TYPE_PENDING_CANCEL  ExecType = 10
+	ExecType_EXEC_TYPE_PENDING_REPLACE ExecType = 11
+)
+
+// Enum value maps for ExecType.
+var (
+	ExecType_name = map[int32]string{
+		0:  "EXEC_TYPE_UNSPECIFIED",
+		1:  "EXEC_TYPE_NEW",
+		2:  "EXEC_TYPE_TRADE",
+		3:  "EXEC_TYPE_TRADE_CANCEL",
+		4:  "EXEC_TYPE_TRADE_CORRECT",
+		5:  "EXEC_TYPE_CANCELED",
+		6:  "EXEC_TYPE_REPLACED",
+		7:  "EXEC_TYPE_REJECTED",
+		8:  "EXEC_TYPE_EXPIRED",
+		9:  "EXEC_TYPE_PENDING_NEW",
+		10: "EXEC_TYPE_PENDING_CANCEL",
+		11: "EXEC_TYPE_PENDING_REPLACE",
+	}
+	ExecType_value = map[string]int32{
+		"EXEC_TYPE_UNSPECIFIED":     0,
+		"EXEC_TYPE_NEW":             1,
+		"EXEC_TYPE_TRADE":           2,
+		"EXEC_TYPE_TRADE_CANCEL":    3,
+		"EXEC_TYPE_TRADE_CORRECT":   4,
+		"EXEC_TYPE_CANCELED":        5,
+		"EXEC_TYPE_REPLACED":        6,
+		"EXEC_TYPE_REJECTED":        7,
+		"EXEC_TYPE_EXPIRED":         8,
+		"EXEC_TYPE_PENDING_NEW":     9,
+		"EXEC_TYPE_PENDING_CANCEL":  10,
+		"EXEC_TYPE_PENDING_REPLACE": 11,
+	}
+)
+
+func (x ExecType) Enum() *ExecType {
+	p := new(ExecType)
+	*p = x
+	return p
+}
+
+func (x ExecType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExecType) Descriptor() protoreflect.EnumDescriptor {
+	return file_mkt_proto_enumTypes[2].Descriptor()
+}
+
+func (ExecType) Type() protoreflect.EnumType {
+	return &file_mkt_proto_enumTypes[2]
+}
+
+func (x ExecType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExecType.Descriptor instead.
+func (ExecType) EnumDescriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{2}
+}
+
+type TimeInForce int32
+
+const (
+	TimeInForce_TIME_IN_FORCE_UNSPECIFIED TimeInForce = 0
+	TimeInForce_TIME_IN_FORCE_GTC         TimeInForce = 1
+	TimeInForce_TIME_IN_FORCE_IOC         TimeInForce = 3
+)
+
+// Enum value maps for TimeInForce.
+var (
+	TimeInForce_name = map[int32]string{
+		0: "TIME_IN_FORCE_UNSPECIFIED",
+		1: "TIME_IN_FORCE_GTC",
+		3: "TIME_IN_FORCE_IOC",
+	}
+	TimeInForce_value = map[string]int32{
+		"TIME_IN_FORCE_UNSPECIFIED": 0,
+		"TIME_IN_FORCE_GTC":         1,
+		"TIME_IN_FORCE_IOC":         3,
+	}
+)
+
+func (x TimeInForce) Enum() *TimeInForce {
+	p := new(TimeInForce)
+	*p = x
+	return p
+}
+
+func (x TimeInForce) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TimeInForce) Descriptor() protoreflect.EnumDescriptor {
+	return file_mkt_proto_enumTypes[3].Descriptor()
+}
+
+func (TimeInForce) Type() protoreflect.EnumType {
+	return &file_mkt_proto_enumTypes[3]
+}
+
+func (x TimeInForce) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TimeInForce.Descriptor instead.
+func (TimeInForce) EnumDescriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{3}
+}
+
+type MsgType int32
+
+const (
+	MsgType_MSG_TYPE_UNSPECIFIED MsgType = 0
+	MsgType_MSG_TYPE_NEW         MsgType = 1
+	MsgType_MSG_TYPE_CANCEL      MsgType = 2
+	MsgType_MSG_TYPE_REPLACE     MsgType = 3
+)
+
+// Enum value maps for MsgType.
+var (
+	MsgType_name = map[int32]string{
+		0: "MSG_TYPE_UNSPECIFIED",
+		1: "MSG_TYPE_NEW",
+		2: "MSG_TYPE_CANCEL",
+		3: "MSG_TYPE_REPLACE",
+	}
+	MsgType_value = map[string]int32{
+		"MSG_TYPE_UNSPECIFIED": 0,
+		"MSG_TYPE_NEW":         1,
+		"MSG_TYPE_CANCEL":      2,
+		"MSG_TYPE_REPLACE":     3,
+	}
+)
+
+func (x MsgType) Enum() *MsgType {
+	p := new(MsgType)
+	*p = x
+	return p
+}
+
+func (x MsgType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MsgType) Descriptor() protoreflect.EnumDescriptor {
+	return file_mkt_proto_enumTypes[4].Descriptor()
+}
+
+func (MsgType) Type() protoreflect.EnumType {
+	return &file_mkt_proto_enumTypes[4]
+}
+
+func (x MsgType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MsgType.Descriptor instead.
+func (MsgType) EnumDescriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{4}
+}
+
+type PutOrCall int32
+
+const (
+	PutOrCall_PUT_OR_CALL_UNSPECIFIED PutOrCall = 0
+	PutOrCall_PUT_OR_CALL_PUT         PutOrCall = 1
+	PutOrCall_PUT_OR_CALL_CALL        PutOrCall = 2
+)
+
+// Enum value maps for PutOrCall.
+var (
+	PutOrCall_name = map[int32]string{
+		0: "PUT_OR_CALL_UNSPECIFIED",
+		1: "PUT_OR_CALL_PUT",
+		2: "PUT_OR_CALL_CALL",
+	}
+	PutOrCall_value = map[string]int32{
+		"PUT_OR_CALL_UNSPECIFIED": 0,
+		"PUT_OR_CALL_PUT":         1,
+		"PUT_OR_CALL_CALL":        2,
+	}
+)
+
+func (x PutOrCall) Enum() *PutOrCall {
+	p := new(PutOrCall)
+	*p = x
+	return p
+}
+
+func (x PutOrCall) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PutOrCall) Descriptor() protoreflect.EnumDescriptor {
+	return file_mkt_proto_enumTypes[5].Descriptor()
+}
+
+func (PutOrCall) Type() protoreflect.EnumType {
+	return &file_mkt_proto_enumTypes[5]
+}
+
+func (x PutOrCall) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PutOrCall.Descriptor instead.
+func (PutOrCall) EnumDescriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{5}
+}
+
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MsgType       MsgType                `protobuf:"varint,1,opt,name=msg_type,json=msgType,proto3,enum=mkt.v1.MsgType" json:"msg_type,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Side          Side                   `protobuf:"varint,3,opt,name=side,proto3,enum=mkt.v1.Side" json:"side,omitempty"`
+	Symbol        string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_mkt_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_mkt_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Order) GetMsgType() MsgType {
+	if x != nil {
+		return x.MsgType
+	}
+	return MsgType_MSG_TYPE_UNSPECIFIED
+}
+
+func (x *Order) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Order) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *Order) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type Report struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OrderId          string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Symbol           string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side             Side                   `protobuf:"varint,3,opt,name=side,proto3,enum=mkt.v1.Side" json:"side,omitempty"`
+	SecondaryOrderId string                 `protobuf:"bytes,4,opt,name=secondary_order_id,json=secondaryOrderId,proto3" json:"secondary_order_id,omitempty"`
+	ClOrdId          string                 `protobuf:"bytes,5,opt,name=cl_ord_id,json=clOrdId,proto3" json:"cl_ord_id,omitempty"`
+	OrdStatus        OrdStatus              `protobuf:"varint,6,opt,name=ord_status,json=ordStatus,proto3,enum=mkt.v1.OrdStatus" json:"ord_status,omitempty"`
+	Account          string                 `protobuf:"bytes,7,opt,name=account,proto3" json:"account,omitempty"`
+	TimeInForce      TimeInForce            `protobuf:"varint,8,opt,name=time_in_force,json=timeInForce,proto3,enum=mkt.v1.TimeInForce" json:"time_in_force,omitempty"`
+	LastQty          string                 `protobuf:"bytes,9,opt,name=last_qty,json=lastQty,proto3" json:"last_qty,omitempty"`
+	LastPx           string                 `protobuf:"bytes,10,opt,name=last_px,json=lastPx,proto3" json:"last_px,omitempty"`
+	TransactTime     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=transact_time,json=transactTime,proto3" json:"transact_time,omitempty"`
+	ExecInst         string                 `protobuf:"bytes,12,opt,name=exec_inst,json=execInst,proto3" json:"exec_inst,omitempty"`
+	ExecId           string                 `protobuf:"bytes,13,opt,name=exec_id,json=execId,proto3" json:"exec_id,omitempty"`
+	ExecType         ExecType               `protobuf:"varint,14,opt,name=exec_type,json=execType,proto3,enum=mkt.v1.ExecType" json:"exec_type,omitempty"`
+	ExecRefId        string                 `protobuf:"bytes,15,opt,name=exec_ref_id,json=execRefId,proto3" json:"exec_ref_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Report) Reset() {
+	*x = Report{}
+	mi := &file_mkt_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Report) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Report) ProtoMessage() {}
+
+func (x *Report) ProtoReflect() protoreflect.Message {
+	mi := &file_mkt_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Report.ProtoReflect.Descriptor instead.
+func (*Report) Descriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Report) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Report) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Report) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *Report) GetSecondaryOrderId() string {
+	if x != nil {
+		return x.SecondaryOrderId
+	}
+	return ""
+}
+
+func (x *Report) GetClOrdId() string {
+	if x != nil {
+		return x.ClOrdId
+	}
+	return ""
+}
+
+func (x *Report) GetOrdStatus() OrdStatus {
+	if x != nil {
+		return x.OrdStatus
+	}
+	return OrdStatus_ORD_STATUS_UNSPECIFIED
+}
+
+func (x *Report) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *Report) GetTimeInForce() TimeInForce {
+	if x != nil {
+		return x.TimeInForce
+	}
+	return TimeInForce_TIME_IN_FORCE_UNSPECIFIED
+}
+
+func (x *Report) GetLastQty() string {
+	if x != nil {
+		return x.LastQty
+	}
+	return ""
+}
+
+func (x *Report) GetLastPx() string {
+	if x != nil {
+		return x.LastPx
+	}
+	return ""
+}
+
+func (x *Report) GetTransactTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TransactTime
+	}
+	return nil
+}
+
+func (x *Report) GetExecInst() string {
+	if x != nil {
+		return x.ExecInst
+	}
+	return ""
+}
+
+func (x *Report) GetExecId() string {
+	if x != nil {
+		return x.ExecId
+	}
+	return ""
+}
+
+func (x *Report) GetExecType() ExecType {
+	if x != nil {
+		return x.ExecType
+	}
+	return ExecType_EXEC_TYPE_UNSPECIFIED
+}
+
+func (x *Report) GetExecRefId() string {
+	if x != nil {
+		return x.ExecRefId
+	}
+	return ""
+}
+
+type Quote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	BidPx         string                 `protobuf:"bytes,2,opt,name=bid_px,json=bidPx,proto3" json:"bid_px,omitempty"`
+	BidSize       string                 `protobuf:"bytes,3,opt,name=bid_size,json=bidSize,proto3" json:"bid_size,omitempty"`
+	AskPx         string                 `protobuf:"bytes,4,opt,name=ask_px,json=askPx,proto3" json:"ask_px,omitempty"`
+	AskSize       string                 `protobuf:"bytes,5,opt,name=ask_size,json=askSize,proto3" json:"ask_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quote) Reset() {
+	*x = Quote{}
+	mi := &file_mkt_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quote) ProtoMessage() {}
+
+func (x *Quote) ProtoReflect() protoreflect.Message {
+	mi := &file_mkt_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quote.ProtoReflect.Descriptor instead.
+func (*Quote) Descriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Quote) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Quote) GetBidPx() string {
+	if x != nil {
+		return x.BidPx
+	}
+	return ""
+}
+
+func (x *Quote) GetBidSize() string {
+	if x != nil {
+		return x.BidSize
+	}
+	return ""
+}
+
+func (x *Quote) GetAskPx() string {
+	if x != nil {
+		return x.AskPx
+	}
+	return ""
+}
+
+func (x *Quote) GetAskSize() string {
+	if x != nil {
+		return x.AskSize
+	}
+	return ""
+}
+
+type Trade struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	LastQty       string                 `protobuf:"bytes,2,opt,name=last_qty,json=lastQty,proto3" json:"last_qty,omitempty"`
+	LastPx        string                 `protobuf:"bytes,3,opt,name=last_px,json=lastPx,proto3" json:"last_px,omitempty"`
+	TradeVolume   string                 `protobuf:"bytes,4,opt,name=trade_volume,json=tradeVolume,proto3" json:"trade_volume,omitempty"`
+	AvgPx         string                 `protobuf:"bytes,5,opt,name=avg_px,json=avgPx,proto3" json:"avg_px,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trade) Reset() {
+	*x = Trade{}
+	mi := &file_mkt_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trade) ProtoMessage() {}
+
+func (x *Trade) ProtoReflect() protoreflect.Message {
+	mi := &file_mkt_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trade.ProtoReflect.Descriptor instead.
+func (*Trade) Descriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Trade) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Trade) GetLastQty() string {
+	if x != nil {
+		return x.LastQty
+	}
+	return ""
+}
+
+func (x *Trade) GetLastPx() string {
+	if x != nil {
+		return x.LastPx
+	}
+	return ""
+}
+
+func (x *Trade) GetTradeVolume() string {
+	if x != nil {
+		return x.TradeVolume
+	}
+	return ""
+}
+
+func (x *Trade) GetAvgPx() string {
+	if x != nil {
+		return x.AvgPx
+	}
+	return ""
+}
+
+type TickBand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Increment     string                 `protobuf:"bytes,2,opt,name=increment,proto3" json:"increment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TickBand) Reset() {
+	*x = TickBand{}
+	mi := &file_mkt_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TickBand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TickBand) ProtoMessage() {}
+
+func (x *TickBand) ProtoReflect() protoreflect.Message {
+	mi := &file_mkt_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TickBand.ProtoReflect.Descriptor instead.
+func (*TickBand) Descriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TickBand) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *TickBand) GetIncrement() string {
+	if x != nil {
+		return x.Increment
+	}
+	return ""
+}
+
+type Instrument struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SecurityType  string                 `protobuf:"bytes,1,opt,name=security_type,json=securityType,proto3" json:"security_type,omitempty"`
+	Expiry        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	Underlying    string                 `protobuf:"bytes,3,opt,name=underlying,proto3" json:"underlying,omitempty"`
+	PutOrCall     PutOrCall              `protobuf:"varint,4,opt,name=put_or_call,json=putOrCall,proto3,enum=mkt.v1.PutOrCall" json:"put_or_call,omitempty"`
+	StrikePrice   string                 `protobuf:"bytes,5,opt,name=strike_price,json=strikePrice,proto3" json:"strike_price,omitempty"`
+	SettlMethod   string                 `protobuf:"bytes,6,opt,name=settl_method,json=settlMethod,proto3" json:"settl_method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Instrument) Reset() {
+	*x = Instrument{}
+	mi := &file_mkt_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Instrument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Instrument) ProtoMessage() {}
+
+func (x *Instrument) ProtoReflect() protoreflect.Message {
+	mi := &file_mkt_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Instrument.ProtoReflect.Descriptor instead.
+func (*Instrument) Descriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Instrument) GetSecurityType() string {
+	if x != nil {
+		return x.SecurityType
+	}
+	return ""
+}
+
+func (x *Instrument) GetExpiry() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expiry
+	}
+	return nil
+}
+
+func (x *Instrument) GetUnderlying() string {
+	if x != nil {
+		return x.Underlying
+	}
+	return ""
+}
+
+func (x *Instrument) GetPutOrCall() PutOrCall {
+	if x != nil {
+		return x.PutOrCall
+	}
+	return PutOrCall_PUT_OR_CALL_UNSPECIFIED
+}
+
+func (x *Instrument) GetStrikePrice() string {
+	if x != nil {
+		return x.StrikePrice
+	}
+	return ""
+}
+
+func (x *Instrument) GetSettlMethod() string {
+	if x != nil {
+		return x.SettlMethod
+	}
+	return ""
+}
+
+type Listing struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Symbol             string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	TickIncrement      string                 `protobuf:"bytes,2,opt,name=tick_increment,json=tickIncrement,proto3" json:"tick_increment,omitempty"`
+	RoundLot           string                 `protobuf:"bytes,3,opt,name=round_lot,json=roundLot,proto3" json:"round_lot,omitempty"`
+	MinTradeVol        string                 `protobuf:"bytes,4,opt,name=min_trade_vol,json=minTradeVol,proto3" json:"min_trade_vol,omitempty"`
+	ContractMultiplier string                 `protobuf:"bytes,5,opt,name=contract_multiplier,json=contractMultiplier,proto3" json:"contract_multiplier,omitempty"`
+	TickTable          []*TickBand            `protobuf:"bytes,6,rep,name=tick_table,json=tickTable,proto3" json:"tick_table,omitempty"`
+	Instrument         *Instrument            `protobuf:"bytes,7,opt,name=instrument,proto3" json:"instrument,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Listing) Reset() {
+	*x = Listing{}
+	mi := &file_mkt_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Listing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Listing) ProtoMessage() {}
+
+func (x *Listing) ProtoReflect() protoreflect.Message {
+	mi := &file_mkt_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Listing.ProtoReflect.Descriptor instead.
+func (*Listing) Descriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Listing) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Listing) GetTickIncrement() string {
+	if x != nil {
+		return x.TickIncrement
+	}
+	return ""
+}
+
+func (x *Listing) GetRoundLot() string {
+	if x != nil {
+		return x.RoundLot
+	}
+	return ""
+}
+
+func (x *Listing) GetMinTradeVol() string {
+	if x != nil {
+		return x.MinTradeVol
+	}
+	return ""
+}
+
+func (x *Listing) GetContractMultiplier() string {
+	if x != nil {
+		return x.ContractMultiplier
+	}
+	return ""
+}
+
+func (x *Listing) GetTickTable() []*TickBand {
+	if x != nil {
+		return x.TickTable
+	}
+	return nil
+}
+
+func (x *Listing) GetInstrument() *Instrument {
+	if x != nil {
+		return x.Instrument
+	}
+	return nil
+}
+
+type PositionMemo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Quantity      string                 `protobuf:"bytes,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	AvgPx         string                 `protobuf:"bytes,3,opt,name=avg_px,json=avgPx,proto3" json:"avg_px,omitempty"`
+	Realised      string                 `protobuf:"bytes,4,opt,name=realised,proto3" json:"realised,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PositionMemo) Reset() {
+	*x = PositionMemo{}
+	mi := &file_mkt_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PositionMemo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PositionMemo) ProtoMessage() {}
+
+func (x *PositionMemo) ProtoReflect() protoreflect.Message {
+	mi := &file_mkt_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PositionMemo.ProtoReflect.Descriptor instead.
+func (*PositionMemo) Descriptor() ([]byte, []int) {
+	return file_mkt_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PositionMemo) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PositionMemo) GetQuantity() string {
+	if x != nil {
+		return x.Quantity
+	}
+	return ""
+}
+
+func (x *PositionMemo) GetAvgPx() string {
+	if x != nil {
+		return x.AvgPx
+	}
+	return ""
+}
+
+func (x *PositionMemo) GetRealised() string {
+	if x != nil {
+		return x.Realised
+	}
+	return ""
+}
+
+var File_mkt_proto protoreflect.FileDescriptor
+
+const file_mkt_proto_rawDesc = "" +
+	"\n" +
+	"\tmkt.proto\x12\x06mkt.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x88\x01\n" +
+	"\x05Order\x12*\n" +
+	"\bmsg_type\x18\x01 \x01(\x0e2\x0f.mkt.v1.MsgTypeR\amsgType\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12 \n" +
+	"\x04side\x18\x03 \x01(\x0e2\f.mkt.v1.SideR\x04side\x12\x16\n" +
+	"\x06symbol\x18\x04 \x01(\tR\x06symbol\"\xa6\x04\n" +
+	"\x06Report\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12 \n" +
+	"\x04side\x18\x03 \x01(\x0e2\f.mkt.v1.SideR\x04side\x12,\n" +
+	"\x12secondary_order_id\x18\x04 \x01(\tR\x10secondaryOrderId\x12\x1a\n" +
+	"\tcl_ord_id\x18\x05 \x01(\tR\aclOrdId\x120\n" +
+	"\n" +
+	"ord_status\x18\x06 \x01(\x0e2\x11.mkt.v1.OrdStatusR\tordStatus\x12\x18\n" +
+	"\aaccount\x18\a \x01(\tR\aaccount\x127\n" +
+	"\rtime_in_force\x18\b \x01(\x0e2\x13.mkt.v1.TimeInForceR\vtimeInForce\x12\x19\n" +
+	"\blast_qty\x18\t \x01(\tR\alastQty\x12\x17\n" +
+	"\alast_px\x18\n" +
+	" \x01(\tR\x06lastPx\x12?\n" +
+	"\rtransact_time\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\ftransactTime\x12\x1b\n" +
+	"\texec_inst\x18\f \x01(\tR\bexecInst\x12\x17\n" +
+	"\aexec_id\x18\r \x01(\tR\x06execId\x12-\n" +
+	"\texec_type\x18\x0e \x01(\x0e2\x10.mkt.v1.ExecTypeR\bexecType\x12\x1e\n" +
+	"\vexec_ref_id\x18\x0f \x01(\tR\texecRefId\"\x83\x01\n" +
+	"\x05Quote\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x15\n" +
+	"\x06bid_px\x18\x02 \x01(\tR\x05bidPx\x12\x19\n" +
+	"\bbid_size\x18\x03 \x01(\tR\abidSize\x12\x15\n" +
+	"\x06ask_px\x18\x04 \x01(\tR\x05askPx\x12\x19\n" +
+	"\bask_size\x18\x05 \x01(\tR\aaskSize\"\x8d\x01\n" +
+	"\x05Trade\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x19\n" +
+	"\blast_qty\x18\x02 \x01(\tR\alastQty\x12\x17\n" +
+	"\alast_px\x18\x03 \x01(\tR\x06lastPx\x12!\n" +
+	"\ftrade_volume\x18\x04 \x01(\tR\vtradeVolume\x12\x15\n" +
+	"\x06avg_px\x18\x05 \x01(\tR\x05avgPx\"<\n" +
+	"\bTickBand\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x1c\n" +
+	"\tincrement\x18\x02 \x01(\tR\tincrement\"\xfe\x01\n" +
+	"\n" +
+	"Instrument\x12#\n" +
+	"\rsecurity_type\x18\x01 \x01(\tR\fsecurityType\x122\n" +
+	"\x06expiry\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x06expiry\x12\x1e\n" +
+	"\n" +
+	"underlying\x18\x03 \x01(\tR\n" +
+	"underlying\x121\n" +
+	"\vput_or_call\x18\x04 \x01(\x0e2\x11.mkt.v1.PutOrCallR\tputOrCall\x12!\n" +
+	"\fstrike_price\x18\x05 \x01(\tR\vstrikePrice\x12!\n" +
+	"\fsettl_method\x18\x06 \x01(\tR\vsettlMethod\"\x9f\x02\n" +
+	"\aListing\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12%\n" +
+	"\x0etick_increment\x18\x02 \x01(\tR\rtickIncrement\x12\x1b\n" +
+	"\tround_lot\x18\x03 \x01(\tR\broundLot\x12\"\n" +
+	"\rmin_trade_vol\x18\x04 \x01(\tR\vminTradeVol\x12/\n" +
+	"\x13contract_multiplier\x18\x05 \x01(\tR\x12contractMultiplier\x12/\n" +
+	"\n" +
+	"tick_table\x18\x06 \x03(\v2\x10.mkt.v1.TickBandR\ttickTable\x122\n" +
+	"\n" +
+	"instrument\x18\a \x01(\v2\x12.mkt.v1.InstrumentR\n" +
+	"instrument\"u\n" +
+	"\fPositionMemo\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\tR\bquantity\x12\x15\n" +
+	"\x06avg_px\x18\x03 \x01(\tR\x05avgPx\x12\x1a\n" +
+	"\brealised\x18\x04 \x01(\tR\brealised*9\n" +
+	"\x04Side\x12\x14\n" +
+	"\x10SIDE_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bSIDE_BUY\x10\x01\x12\r\n" +
+	"\tSIDE_SELL\x10\x02*\x98\x02\n" +
+	"\tOrdStatus\x12\x1a\n" +
+	"\x16ORD_STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eORD_STATUS_NEW\x10\x01\x12\x1f\n" +
+	"\x1bORD_STATUS_PARTIALLY_FILLED\x10\x02\x12\x15\n" +
+	"\x11ORD_STATUS_FILLED\x10\x03\x12\x17\n" +
+	"\x13ORD_STATUS_CANCELED\x10\x04\x12\x1d\n" +
+	"\x19ORD_STATUS_PENDING_CANCEL\x10\x05\x12\x17\n" +
+	"\x13ORD_STATUS_REJECTED\x10\x06\x12\x1a\n" +
+	"\x16ORD_STATUS_PENDING_NEW\x10\a\x12\x16\n" +
+	"\x12ORD_STATUS_EXPIRED\x10\b\x12\x1e\n" +
+	"\x1aORD_STATUS_PENDING_REPLACE\x10\t*\xbd\x02\n" +
+	"\bExecType\x12\x19\n" +
+	"\x15EXEC_TYPE_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rEXEC_TYPE_NEW\x10\x01\x12\x13\n" +
+	"\x0fEXEC_TYPE_TRADE\x10\x02\x12\x1a\n" +
+	"\x16EXEC_TYPE_TRADE_CANCEL\x10\x03\x12\x1b\n" +
+	"\x17EXEC_TYPE_TRADE_CORRECT\x10\x04\x12\x16\n" +
+	"\x12EXEC_TYPE_CANCELED\x10\x05\x12\x16\n" +
+	"\x12EXEC_TYPE_REPLACED\x10\x06\x12\x16\n" +
+	"\x12EXEC_TYPE_REJECTED\x10\a\x12\x15\n" +
+	"\x11EXEC_TYPE_EXPIRED\x10\b\x12\x19\n" +
+	"\x15EXEC_TYPE_PENDING_NEW\x10\t\x12\x1c\n" +
+	"\x18EXEC_TYPE_PENDING_CANCEL\x10\n" +
+	"\x12\x1d\n" +
+	"\x19EXEC_TYPE_PENDING_REPLACE\x10\v*Z\n" +
+	"\vTimeInForce\x12\x1d\n" +
+	"\x19TIME_IN_FORCE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11TIME_IN_FORCE_GTC\x10\x01\x12\x15\n" +
+	"\x11TIME_IN_FORCE_IOC\x10\x03*`\n" +
+	"\aMsgType\x12\x18\n" +
+	"\x14MSG_TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fMSG_TYPE_NEW\x10\x01\x12\x13\n" +
+	"\x0fMSG_TYPE_CANCEL\x10\x02\x12\x14\n" +
+	"\x10MSG_TYPE_REPLACE\x10\x03*S\n" +
+	"\tPutOrCall\x12\x1b\n" +
+	"\x17PUT_OR_CALL_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fPUT_OR_CALL_PUT\x10\x01\x12\x14\n" +
+	"\x10PUT_OR_CALL_CALL\x10\x02B\x1fZ\x1dgithub.com/gbkr-com/mkt/mktpbb\x06proto3"
+
+var (
+	file_mkt_proto_rawDescOnce sync.Once
+	file_mkt_proto_rawDescData []byte
+)
+
+func file_mkt_proto_rawDescGZIP() []byte {
+	file_mkt_proto_rawDescOnce.Do(func() {
+		file_mkt_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_mkt_proto_rawDesc), len(file_mkt_proto_rawDesc)))
+	})
+	return file_mkt_proto_rawDescData
+}
+
+var file_mkt_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_mkt_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_mkt_proto_goTypes = []any{
+	(Side)(0),                     // 0: mkt.v1.Side
+	(OrdStatus)(0),                // 1: mkt.v1.OrdStatus
+	(ExecType)(0),                 // 2: mkt.v1.ExecType
+	(TimeInForce)(0),              // 3: mkt.v1.TimeInForce
+	(MsgType)(0),                  // 4: mkt.v1.MsgType
+	(PutOrCall)(0),                // 5: mkt.v1.PutOrCall
+	(*Order)(nil),                 // 6: mkt.v1.Order
+	(*Report)(nil),                // 7: mkt.v1.Report
+	(*Quote)(nil),                 // 8: mkt.v1.Quote
+	(*Trade)(nil),                 // 9: mkt.v1.Trade
+	(*TickBand)(nil),              // 10: mkt.v1.TickBand
+	(*Instrument)(nil),            // 11: mkt.v1.Instrument
+	(*Listing)(nil),               // 12: mkt.v1.Listing
+	(*PositionMemo)(nil),          // 13: mkt.v1.PositionMemo
+	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
+}
+var file_mkt_proto_depIdxs = []int32{
+	4,  // 0: mkt.v1.Order.msg_type:type_name -> mkt.v1.MsgType
+	0,  // 1: mkt.v1.Order.side:type_name -> mkt.v1.Side
+	0,  // 2: mkt.v1.Report.side:type_name -> mkt.v1.Side
+	1,  // 3: mkt.v1.Report.ord_status:type_name -> mkt.v1.OrdStatus
+	3,  // 4: mkt.v1.Report.time_in_force:type_name -> mkt.v1.TimeInForce
+	14, // 5: mkt.v1.Report.transact_time:type_name -> google.protobuf.Timestamp
+	2,  // 6: mkt.v1.Report.exec_type:type_name -> mkt.v1.ExecType
+	14, // 7: mkt.v1.Instrument.expiry:type_name -> google.protobuf.Timestamp
+	5,  // 8: mkt.v1.Instrument.put_or_call:type_name -> mkt.v1.PutOrCall
+	10, // 9: mkt.v1.Listing.tick_table:type_name -> mkt.v1.TickBand
+	11, // 10: mkt.v1.Listing.instrument:type_name -> mkt.v1.Instrument
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_mkt_proto_init() }
+func file_mkt_proto_init() {
+	if File_mkt_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mkt_proto_rawDesc), len(file_mkt_proto_rawDesc)),
+			NumEnums:      6,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_mkt_proto_goTypes,
+		DependencyIndexes: file_mkt_proto_depIdxs,
+		EnumInfos:         file_mkt_proto_enumTypes,
+		MessageInfos:      file_mkt_proto_msgTypes,
+	}.Build()
+	File_mkt_proto = out.File
+	file_mkt_proto_goTypes = nil
+	file_mkt_proto_depIdxs = nil
+}
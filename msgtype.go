@@ -2,6 +2,7 @@ package mkt
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/quickfixgo/enum"
 	"github.com/quickfixgo/field"
@@ -61,6 +62,22 @@ func MsgTypeFromString(s string) MsgType {
 	}
 }
 
+// ParseMsgType returns the [MsgType] for the mnemonic, the long name or the
+// FIX code. Unlike [MsgTypeFromString] it returns an error for any other
+// value.
+func ParseMsgType(s string) (MsgType, error) {
+	switch s {
+	case "NEW", "ORDER_SINGLE", string(enum.MsgType_ORDER_SINGLE):
+		return OrderNew, nil
+	case "CXL", "ORDER_CANCEL_REQUEST", string(enum.MsgType_ORDER_CANCEL_REQUEST):
+		return OrderCancel, nil
+	case "RPL", "ORDER_CANCEL_REPLACE_REQUEST", string(enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST):
+		return OrderReplace, nil
+	default:
+		return 0, fmt.Errorf("mkt.ParseMsgType: unknown value %q", s)
+	}
+}
+
 // MarshalJSON implements json.Marshaler.
 func (x MsgType) MarshalJSON() ([]byte, error) {
 	return json.Marshal(x.String())
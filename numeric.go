@@ -12,8 +12,9 @@ func Precision(number decimal.Decimal) int32 {
 }
 
 // Units returns x as an integer number of units greater than or equal to the
-// minimum. This function is used for both prices (to be round ticks) and
-// quantities (to be round lots).
+// minimum, such as a quantity in round lots. A single unit cannot follow the
+// price bands of a [TickTable], so prices are rounded to ticks with
+// [Listing.RoundPrice], which uses the table if there is one.
 func Units(x, unit, min decimal.Decimal) decimal.Decimal {
 	if x.LessThanOrEqual(decimal.Zero) {
 		return decimal.Zero
@@ -0,0 +1,226 @@
+package mkt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OptionModel is the pricing model for European options.
+type OptionModel int64
+
+// Recognised OptionModel values.
+const (
+	BlackScholes OptionModel = 1 // Options on a spot price, with a continuous yield.
+	Black76      OptionModel = 2 // Options on a forward or futures price.
+)
+
+// OptionInputs are everything needed to price an option. Rates, yield and
+// volatility are annual and continuously compounded; for [Black76] the
+// Underlying is the forward price and the Yield is ignored.
+type OptionInputs struct {
+	Model      OptionModel
+	PutOrCall  PutOrCall
+	Underlying float64
+	Strike     float64
+	Years      float64 // Time to expiry, taken as zero if negative.
+	Rate       float64
+	Yield      float64
+	Vol        float64
+}
+
+// Greeks are the theoretical value of an option and its sensitivities. Vega
+// is per unit of volatility, so per 100 vol points, and Theta is per year.
+type Greeks struct {
+	Value float64
+	Delta float64
+	Gamma float64
+	Vega  float64
+	Theta float64
+}
+
+// NewOptionInputs returns the [OptionInputs] for an option listing at the
+// time, taking the strike, put or call and expiry from its [Instrument].
+func NewOptionInputs(listing *Listing, model OptionModel, underlying decimal.Decimal, t time.Time, rate, vol float64) (OptionInputs, error) {
+
+	instrument := listing.Instrument
+	if instrument == nil || instrument.SecurityType != Option {
+		return OptionInputs{}, fmt.Errorf("mkt.NewOptionInputs: %s is not an option", listing.Symbol)
+	}
+	if instrument.PutOrCall != Put && instrument.PutOrCall != Call {
+		return OptionInputs{}, fmt.Errorf("mkt.NewOptionInputs: %s is neither put nor call", listing.Symbol)
+	}
+
+	return OptionInputs{
+		Model:      model,
+		PutOrCall:  instrument.PutOrCall,
+		Underlying: underlying.InexactFloat64(),
+		Strike:     instrument.StrikePrice.InexactFloat64(),
+		Years:      max(instrument.Expiry.Sub(t).Hours()/(365*24), 0),
+		Rate:       rate,
+		Vol:        vol,
+	}, nil
+
+}
+
+// PriceOption returns the theoretical value and [Greeks] of the option. At or
+// after expiry, or with no volatility, the value is the discounted intrinsic
+// value.
+func PriceOption(in OptionInputs) Greeks {
+
+	in.Years = max(in.Years, 0)
+	q := in.Yield
+	if in.Model == Black76 {
+		q = in.Rate
+	}
+	growth := math.Exp(-q * in.Years)
+	discount := math.Exp(-in.Rate * in.Years)
+	s, k := in.Underlying*growth, in.Strike*discount
+
+	if in.Years <= 0 || in.Vol <= 0 {
+		var greeks Greeks
+		switch {
+		case in.PutOrCall == Call && s > k:
+			greeks.Value, greeks.Delta = s-k, growth
+		case in.PutOrCall == Put && k > s:
+			greeks.Value, greeks.Delta = k-s, -growth
+		}
+		return greeks
+	}
+
+	root := math.Sqrt(in.Years)
+	d1 := (math.Log(in.Underlying/in.Strike) + (in.Rate-q+in.Vol*in.Vol/2)*in.Years) / (in.Vol * root)
+	d2 := d1 - in.Vol*root
+
+	greeks := Greeks{
+		Gamma: growth * normPDF(d1) / (in.Underlying * in.Vol * root),
+		Vega:  s * normPDF(d1) * root,
+	}
+	decay := -s * normPDF(d1) * in.Vol / (2 * root)
+
+	if in.PutOrCall == Call {
+		greeks.Value = s*normCDF(d1) - k*normCDF(d2)
+		greeks.Delta = growth * normCDF(d1)
+		greeks.Theta = decay - in.Rate*k*normCDF(d2) + q*s*normCDF(d1)
+	} else {
+		greeks.Value = k*normCDF(-d2) - s*normCDF(-d1)
+		greeks.Delta = -growth * normCDF(-d1)
+		greeks.Theta = decay + in.Rate*k*normCDF(-d2) - q*s*normCDF(-d1)
+	}
+	return greeks
+
+}
+
+// ImpliedVol returns the volatility at which the option is worth the price,
+// ignoring the Vol in the inputs. It returns an error if the price is outside
+// the bounds of any volatility.
+func ImpliedVol(in OptionInputs, price float64) (float64, error) {
+
+	if in.Years <= 0 {
+		return 0, errors.New("mkt.ImpliedVol: expired")
+	}
+
+	in.Vol = 0
+	lower := PriceOption(in).Value
+	in.Vol = maxVol
+	upper := PriceOption(in).Value
+	if price <= lower || price >= upper {
+		return 0, fmt.Errorf("mkt.ImpliedVol: price %g is not between %g and %g", price, lower, upper)
+	}
+
+	//
+	// Newton-Raphson, falling back to bisection whenever a step would leave
+	// the bracket.
+	//
+	low, high := 0.0, maxVol
+	vol := 0.2
+	for i := 0; i < 100; i++ {
+		in.Vol = vol
+		greeks := PriceOption(in)
+		diff := greeks.Value - price
+		if math.Abs(diff) < 1e-10 {
+			return vol, nil
+		}
+		if diff > 0 {
+			high = vol
+		} else {
+			low = vol
+		}
+		next := vol - diff/greeks.Vega
+		if greeks.Vega <= 0 || next <= low || next >= high {
+			next = (low + high) / 2
+		}
+		vol = next
+	}
+	return vol, nil
+
+}
+
+// ImpliedVolFromQuote returns the [ImpliedVol] at the mid price of the quote.
+func ImpliedVolFromQuote(in OptionInputs, quote *Quote) (float64, error) {
+	mid := quote.MidPrice()
+	if !mid.IsPositive() {
+		return 0, errors.New("mkt.ImpliedVol: no mid price")
+	}
+	return ImpliedVol(in, mid.InexactFloat64())
+}
+
+// maxVol is the upper bound when solving for implied volatility.
+const maxVol = 10.0
+
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+func normCDF(x float64) float64 {
+	return math.Erfc(-x/math.Sqrt2) / 2
+}
+
+// Delta returns the delta-adjusted exposure of the book to the underlying, in
+// units of the underlying. Each position counts its quantity times its
+// contract multiplier times its delta, where the delta of the underlying
+// itself and of any future on it is one, and the delta of each option is
+// taken from the [Greeks] given by symbol. Positions whose listing has left
+// the white list, such as after expiry, still count. It returns an error if
+// an option position on the underlying has no Greeks.
+func (x *Book[T]) Delta(underlying string, greeks map[string]Greeks) (decimal.Decimal, error) {
+
+	exposure := decimal.Zero
+
+	for symbol, position := range x.positions {
+
+		if position.quantity.IsZero() {
+			continue
+		}
+		def := position.definition()
+		if def == nil {
+			continue
+		}
+
+		delta := DecimalOne
+		switch {
+		case symbol == underlying:
+		case def.Instrument == nil || def.Instrument.Underlying != underlying:
+			continue
+		case def.Instrument.SecurityType == Option:
+			g, ok := greeks[symbol]
+			if !ok {
+				return decimal.Zero, fmt.Errorf("mkt.Book: no greeks for %s", symbol)
+			}
+			delta = decimal.NewFromFloat(g.Delta)
+		}
+
+		multiplier := def.ContractMultiplier
+		if multiplier.IsZero() {
+			multiplier = DecimalOne
+		}
+		exposure = exposure.Add(position.quantity.Mul(multiplier).Mul(delta))
+
+	}
+
+	return exposure, nil
+
+}
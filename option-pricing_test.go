@@ -0,0 +1,156 @@
+package mkt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceOptionBlackScholes(t *testing.T) {
+
+	in := OptionInputs{
+		Model:      BlackScholes,
+		PutOrCall:  Call,
+		Underlying: 100,
+		Strike:     100,
+		Years:      1,
+		Rate:       0.05,
+		Vol:        0.2,
+	}
+
+	call := PriceOption(in)
+	assert.InDelta(t, 10.4506, call.Value, 1e-4)
+	assert.InDelta(t, 0.6368, call.Delta, 1e-4)
+	assert.InDelta(t, 0.01876, call.Gamma, 1e-5)
+	assert.InDelta(t, 37.524, call.Vega, 1e-3)
+	assert.InDelta(t, -6.414, call.Theta, 1e-3)
+
+	in.PutOrCall = Put
+	put := PriceOption(in)
+	assert.InDelta(t, 5.5735, put.Value, 1e-4)
+	assert.InDelta(t, call.Delta-1, put.Delta, 1e-9)
+	assert.InDelta(t, call.Gamma, put.Gamma, 1e-9)
+
+	in.Years = 0
+	assert.Equal(t, Greeks{}, PriceOption(in), "expired out of the money")
+	in.Strike = 110
+	assert.InDelta(t, 10, PriceOption(in).Value, 1e-9)
+	in.Years = -1
+	assert.InDelta(t, 10, PriceOption(in).Value, 1e-9, "after expiry")
+	assert.InDelta(t, -1, PriceOption(in).Delta, 1e-9, "after expiry")
+
+}
+
+func TestPriceOptionBlack76(t *testing.T) {
+
+	in := OptionInputs{
+		Model:      Black76,
+		PutOrCall:  Call,
+		Underlying: 100,
+		Strike:     100,
+		Years:      1,
+		Rate:       0.05,
+		Yield:      0.5,
+		Vol:        0.2,
+	}
+
+	call := PriceOption(in)
+	assert.InDelta(t, 7.5771, call.Value, 1e-4)
+
+	in.PutOrCall = Put
+	assert.InDelta(t, call.Value, PriceOption(in).Value, 1e-9, "at the money forward")
+
+}
+
+func TestImpliedVol(t *testing.T) {
+
+	in := OptionInputs{
+		Model:      BlackScholes,
+		PutOrCall:  Put,
+		Underlying: 100,
+		Strike:     90,
+		Years:      0.5,
+		Rate:       0.03,
+		Yield:      0.01,
+		Vol:        0.35,
+	}
+
+	price := PriceOption(in).Value
+	vol, err := ImpliedVol(in, price)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.35, vol, 1e-8)
+
+	_, err = ImpliedVol(in, 0)
+	assert.NotNil(t, err)
+	_, err = ImpliedVol(in, 1000)
+	assert.NotNil(t, err)
+
+	quote := &Quote{BidPx: decimal.NewFromFloat(price - 0.05), AskPx: decimal.NewFromFloat(price + 0.05)}
+	vol, err = ImpliedVolFromQuote(in, quote)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.35, vol, 1e-6)
+
+	_, err = ImpliedVolFromQuote(in, &Quote{})
+	assert.NotNil(t, err)
+
+}
+
+func TestBookDelta(t *testing.T) {
+
+	expiry := time.Date(2026, 12, 18, 14, 30, 0, 0, time.UTC)
+	option := &Listing{
+		Symbol:             "SPX C5000",
+		ContractMultiplier: decimal.New(100, 0),
+		Instrument: &Instrument{
+			SecurityType: Option,
+			Expiry:       expiry,
+			Underlying:   "SPX",
+			PutOrCall:    Call,
+			StrikePrice:  decimal.New(5000, 0),
+		},
+	}
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "SPX", ContractMultiplier: DecimalOne})
+	whitelist.Add(testFuture("ESZ6", expiry))
+	whitelist.Add(option)
+	whitelist.Add(&Listing{Symbol: "A"})
+
+	book := NewBook("OPT", whitelist)
+	assert.Nil(t, book.Traded("SPX", Buy, decimal.New(100, 0), decimal.New(5000, 0)))
+	assert.Nil(t, book.Traded("ESZ6", Sell, decimal.New(2, 0), decimal.New(5000, 0)))
+	assert.Nil(t, book.Traded("SPX C5000", Buy, decimal.New(3, 0), decimal.New(50, 0)))
+	assert.Nil(t, book.Traded("A", Buy, decimal.New(3, 0), decimal.New(50, 0)))
+
+	_, err := book.Delta("SPX", nil)
+	assert.NotNil(t, err)
+
+	in, err := NewOptionInputs(option, BlackScholes, decimal.New(5000, 0), expiry.Add(-365*24*time.Hour), 0.05, 0.2)
+	assert.Nil(t, err)
+	assert.InDelta(t, 1.0, in.Years, 1e-9)
+	greeks := PriceOption(in)
+	assert.InDelta(t, 0.6368, greeks.Delta, 1e-4)
+
+	in, err = NewOptionInputs(option, BlackScholes, decimal.New(5100, 0), expiry.Add(30*24*time.Hour), 0.05, 0.2)
+	assert.Nil(t, err)
+	assert.Zero(t, in.Years, "after expiry")
+	assert.InDelta(t, 100, PriceOption(in).Value, 1e-9)
+
+	delta, err := book.Delta("SPX", map[string]Greeks{"SPX C5000": {Delta: 0.5}})
+	assert.Nil(t, err)
+	assert.True(t, delta.Equal(decimal.New(100-100+150, 0)), delta.String())
+
+	//
+	// A position still counts once its listing has left the white list.
+	//
+	whitelist.Remove("ESZ6")
+	delta, err = book.Delta("SPX", map[string]Greeks{"SPX C5000": {Delta: 0.5}})
+	assert.Nil(t, err)
+	assert.True(t, delta.Equal(decimal.New(100-100+150, 0)), delta.String())
+
+	_, err = NewOptionInputs(&Listing{Symbol: "A"}, BlackScholes, decimal.Zero, expiry, 0, 0)
+	assert.NotNil(t, err)
+
+}
@@ -1,6 +1,9 @@
 package mkt
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
 
 // Order is the prototype for an order sent to a counterparty.
 type Order struct {
@@ -20,3 +23,12 @@ type AnyOrder interface {
 
 // NewOrderID is a convenience function to generate a unique OrderID.
 func NewOrderID() string { return uuid.NewString() }
+
+// Ticket is an [Order] together with the terms needed to work it. A zero
+// [Ticket.Price] means a market order.
+type Ticket struct {
+	Order
+	OrderQty    decimal.Decimal `json:"orderQty"`              // FIX field 38
+	Price       decimal.Decimal `json:"price"`                 // FIX field 44
+	TimeInForce TimeInForce     `json:"timeInForce,omitempty"` // FIX field 59
+}
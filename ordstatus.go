@@ -2,6 +2,7 @@ package mkt
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/quickfixgo/enum"
 	"github.com/quickfixgo/field"
@@ -27,6 +28,7 @@ const (
 var (
 	ordStatusToString map[OrdStatus]string
 	stringToOrdStatus map[string]OrdStatus
+	longToOrdStatus   map[string]OrdStatus
 )
 
 func init() {
@@ -52,6 +54,17 @@ func init() {
 		"EXPD": OrdStatusExpired,
 		"PRPL": OrdStatusPendingReplace,
 	}
+	longToOrdStatus = map[string]OrdStatus{
+		"NEW":              OrdStatusNew,
+		"PARTIALLY_FILLED": OrdStatusPartiallyFilled,
+		"FILLED":           OrdStatusFilled,
+		"CANCELED":         OrdStatusCanceled,
+		"PENDING_CANCEL":   OrdStatusPendingCancel,
+		"REJECTED":         OrdStatusRejected,
+		"PENDING_NEW":      OrdStatusPendingNew,
+		"EXPIRED":          OrdStatusExpired,
+		"PENDING_REPLACE":  OrdStatusPendingReplace,
+	}
 }
 
 // String returns a mnemonic of the [OrdStatus].
@@ -64,6 +77,22 @@ func OrdStatusFromString(s string) OrdStatus {
 	return stringToOrdStatus[s]
 }
 
+// ParseOrdStatus returns the [OrdStatus] for the mnemonic, the long name or
+// the FIX code. Unlike [OrdStatusFromString] it returns an error for any other
+// value.
+func ParseOrdStatus(s string) (OrdStatus, error) {
+	if x, ok := stringToOrdStatus[s]; ok {
+		return x, nil
+	}
+	if x, ok := longToOrdStatus[s]; ok {
+		return x, nil
+	}
+	if x := OrdStatusFromFIX(field.NewOrdStatus(enum.OrdStatus(s))); x != 0 {
+		return x, nil
+	}
+	return 0, fmt.Errorf("mkt.ParseOrdStatus: unknown value %q", s)
+}
+
 // MarshalJSON implements [json.Marshaler].
 func (x OrdStatus) MarshalJSON() ([]byte, error) {
 	return json.Marshal(x.String())
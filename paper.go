@@ -0,0 +1,422 @@
+package mkt
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Paper trades a [Book] by filling orders against market data rather than
+// sending them to a counterparty. Fills are applied to the book, so any
+// [WithBookChannel] receives the same [*PositionMemo] output as live trading.
+//
+// How and when orders are filled is decided by the [FillModel]. An order whose
+// symbol is removed from the white list is canceled instead of being filled,
+// and an order whose fill the book refuses is rejected. Paper is not safe for
+// concurrent use.
+type Paper[T AnyListing] struct {
+	book    *Book[T]
+	model   FillModel
+	working []*PaperOrder     // In arrival sequence.
+	quotes  map[string]*Quote // The last quote for each symbol.
+	clock   func() time.Time  //
+	reports chan *Report      // Optional channel.
+}
+
+// PaperOrder is a working order in [Paper].
+type PaperOrder struct {
+	Ticket
+	CumQty    decimal.Decimal
+	AvgPx     decimal.Decimal
+	LeavesQty decimal.Decimal
+	Ahead     decimal.Decimal // Estimated quantity ahead in the queue, see [QueueFill].
+	queued    bool
+}
+
+// FillModel decides the quantity and price at which a working order is filled
+// by a quote or a trade. A zero quantity means no fill. The quantity may
+// exceed the leaves quantity of the order; [Paper] limits and rounds it.
+type FillModel interface {
+	OnQuote(order *PaperOrder, quote *Quote) (lastQty, lastPx decimal.Decimal)
+	OnTrade(order *PaperOrder, trade *Trade) (lastQty, lastPx decimal.Decimal)
+}
+
+// PaperOption is any option that can be applied when constructing [Paper].
+type PaperOption[T AnyListing] func(*Paper[T])
+
+// WithPaperClock sets the source of TransactTime for reports. The default is
+// [time.Now].
+func WithPaperClock[T AnyListing](clock func() time.Time) PaperOption[T] {
+	return func(paper *Paper[T]) {
+		paper.clock = clock
+	}
+}
+
+// WithPaperReports writes a [*Report] to the channel for every change to an
+// order.
+func WithPaperReports[T AnyListing](c chan *Report) PaperOption[T] {
+	return func(paper *Paper[T]) {
+		paper.reports = c
+	}
+}
+
+// NewPaper returns a [*Paper] that fills orders into the book using the model.
+func NewPaper[T AnyListing](book *Book[T], model FillModel, options ...PaperOption[T]) *Paper[T] {
+	paper := &Paper[T]{
+		book:   book,
+		model:  model,
+		quotes: map[string]*Quote{},
+		clock:  time.Now,
+	}
+	for _, option := range options {
+		option(paper)
+	}
+	return paper
+}
+
+// Book returns the [*Book] being traded.
+func (x *Paper[T]) Book() *Book[T] { return x.book }
+
+// Submit a new order, cancel or replace according to [Ticket.MsgType]. A new
+// or replaced order is evaluated at once against the last quote for the
+// symbol; any remainder of an IOC order is then canceled.
+func (x *Paper[T]) Submit(ticket *Ticket) error {
+
+	if ticket == nil {
+		return errors.New("mkt.Paper: nil ticket")
+	}
+
+	switch ticket.MsgType {
+
+	case OrderNew:
+		if _, ok := x.book.whitelist.Lookup(ticket.Symbol); !ok {
+			x.report(&PaperOrder{Ticket: *ticket}, OrdStatusRejected, decimal.Zero, decimal.Zero)
+			return fmt.Errorf("mkt.Paper: %s is not whitelisted", ticket.Symbol)
+		}
+		if x.find(ticket.OrderID) != nil {
+			return fmt.Errorf("mkt.Paper: duplicate OrderID %s", ticket.OrderID)
+		}
+		order := &PaperOrder{Ticket: *ticket, LeavesQty: ticket.OrderQty}
+		x.working = append(x.working, order)
+		x.report(order, OrdStatusNew, decimal.Zero, decimal.Zero)
+		return x.evaluate(order)
+
+	case OrderCancel:
+		order := x.find(ticket.OrderID)
+		if order == nil {
+			return fmt.Errorf("mkt.Paper: unknown OrderID %s", ticket.OrderID)
+		}
+		x.cancel(order)
+		return nil
+
+	case OrderReplace:
+		order := x.find(ticket.OrderID)
+		if order == nil {
+			return fmt.Errorf("mkt.Paper: unknown OrderID %s", ticket.OrderID)
+		}
+		leavesQty := ticket.OrderQty.Sub(order.CumQty)
+		if !leavesQty.IsPositive() {
+			return fmt.Errorf("mkt.Paper: OrderQty %s is not above CumQty %s", ticket.OrderQty, order.CumQty)
+		}
+		if !ticket.Price.Equal(order.Price) || leavesQty.GreaterThan(order.LeavesQty) {
+			order.queued = false
+		}
+		order.OrderQty, order.Price, order.LeavesQty = ticket.OrderQty, ticket.Price, leavesQty
+		order.TimeInForce = ticket.TimeInForce
+		status := OrdStatusNew
+		if order.CumQty.IsPositive() {
+			status = OrdStatusPartiallyFilled
+		}
+		x.report(order, status, decimal.Zero, decimal.Zero)
+		return x.evaluate(order)
+
+	default:
+		return fmt.Errorf("mkt.Paper: unsupported MsgType %d", ticket.MsgType)
+	}
+
+}
+
+// OnQuote evaluates the working orders for the quote's symbol.
+func (x *Paper[T]) OnQuote(quote *Quote) {
+	if quote == nil {
+		return
+	}
+	last := *quote
+	x.quotes[quote.Symbol] = &last
+	for _, order := range x.orders(quote.Symbol) {
+		lastQty, lastPx := x.model.OnQuote(order, &last)
+		_ = x.fill(order, lastQty, lastPx) // Reported as a reject.
+	}
+}
+
+// OnTrade evaluates the working orders for the trade's symbol.
+func (x *Paper[T]) OnTrade(trade *Trade) {
+	if trade == nil {
+		return
+	}
+	for _, order := range x.orders(trade.Symbol) {
+		lastQty, lastPx := x.model.OnTrade(order, trade)
+		_ = x.fill(order, lastQty, lastPx) // Reported as a reject.
+	}
+}
+
+// Working returns the working orders, in arrival sequence.
+func (x *Paper[T]) Working() []*PaperOrder {
+	return append([]*PaperOrder(nil), x.working...)
+}
+
+// evaluate a new or replaced order against the last quote, canceling any
+// remainder of an IOC order.
+func (x *Paper[T]) evaluate(order *PaperOrder) error {
+	var err error
+	if quote := x.quotes[order.Symbol]; quote != nil {
+		lastQty, lastPx := x.model.OnQuote(order, quote)
+		err = x.fill(order, lastQty, lastPx)
+	}
+	if order.TimeInForce == IOC && order.LeavesQty.IsPositive() {
+		x.cancel(order)
+	}
+	return err
+}
+
+func (x *Paper[T]) orders(symbol string) []*PaperOrder {
+	var orders []*PaperOrder
+	for _, order := range x.working {
+		if order.Symbol == symbol {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
+
+func (x *Paper[T]) find(orderID string) *PaperOrder {
+	for _, order := range x.working {
+		if order.OrderID == orderID {
+			return order
+		}
+	}
+	return nil
+}
+
+// fill the order, returning an error if the book refuses the fill, in which
+// case the order is rejected.
+func (x *Paper[T]) fill(order *PaperOrder, lastQty, lastPx decimal.Decimal) error {
+
+	if !lastQty.IsPositive() || !order.LeavesQty.IsPositive() {
+		return nil
+	}
+
+	//
+	// The symbol may have been removed from the white list since the order
+	// was accepted, in which case it cannot be traded.
+	//
+	listing, ok := x.book.whitelist.Lookup(order.Symbol)
+	if !ok {
+		x.cancel(order)
+		return nil
+	}
+
+	lastQty = decimal.Min(lastQty, order.LeavesQty)
+	if !lastQty.Equal(order.LeavesQty) {
+		lastQty = Units(lastQty, listing.Definition().RoundLot, decimal.Zero)
+		if lastQty.IsZero() {
+			return nil
+		}
+	}
+
+	if err := x.book.Traded(order.Symbol, order.Side, lastQty, lastPx); err != nil {
+		x.remove(order)
+		order.LeavesQty = decimal.Zero
+		x.report(order, OrdStatusRejected, decimal.Zero, decimal.Zero)
+		return err
+	}
+
+	precision, _ := fromListing(x.book.whitelist, order.Symbol)
+	order.CumQty, order.AvgPx = CumQtyAvgPx(order.CumQty, order.AvgPx, lastQty, lastPx, precision)
+	order.LeavesQty = order.LeavesQty.Sub(lastQty)
+
+	if order.LeavesQty.IsPositive() {
+		x.report(order, OrdStatusPartiallyFilled, lastQty, lastPx)
+		return nil
+	}
+	x.remove(order)
+	x.report(order, OrdStatusFilled, lastQty, lastPx)
+	return nil
+
+}
+
+func (x *Paper[T]) cancel(order *PaperOrder) {
+	x.remove(order)
+	order.LeavesQty = decimal.Zero
+	x.report(order, OrdStatusCanceled, decimal.Zero, decimal.Zero)
+}
+
+func (x *Paper[T]) remove(order *PaperOrder) {
+	for i, other := range x.working {
+		if other == order {
+			x.working = append(x.working[:i], x.working[i+1:]...)
+			return
+		}
+	}
+}
+
+func (x *Paper[T]) report(order *PaperOrder, status OrdStatus, lastQty, lastPx decimal.Decimal) {
+	if x.reports == nil {
+		return
+	}
+	x.reports <- &Report{
+		OrderID:      order.OrderID,
+		Symbol:       order.Symbol,
+		Side:         order.Side,
+		OrdStatus:    status,
+		TimeInForce:  order.TimeInForce,
+		LastQty:      lastQty,
+		LastPx:       lastPx,
+		TransactTime: x.clock(),
+	}
+}
+
+// TouchFill fills an order when the market touches its limit: either the far
+// side of the quote is at or through the limit, filling at the far price and
+// up to the far size, or a trade prints at or through the limit, filling the
+// whole order at the limit. A market order fills at the far price.
+type TouchFill struct{}
+
+// OnQuote implements [FillModel].
+func (TouchFill) OnQuote(order *PaperOrder, quote *Quote) (decimal.Decimal, decimal.Decimal) {
+	return crossed(order, quote)
+}
+
+// OnTrade implements [FillModel].
+func (TouchFill) OnTrade(order *PaperOrder, trade *Trade) (decimal.Decimal, decimal.Decimal) {
+	if order.Price.IsZero() {
+		return decimal.Zero, decimal.Zero
+	}
+	_, px := traded(trade)
+	if px.IsZero() || !order.Side.Within(px, order.Price) {
+		return decimal.Zero, decimal.Zero
+	}
+	return order.LeavesQty, order.Price
+}
+
+// MidFill fills the whole order at the mid price of the quote, whenever that
+// is within the limit.
+type MidFill struct{}
+
+// OnQuote implements [FillModel].
+func (MidFill) OnQuote(order *PaperOrder, quote *Quote) (decimal.Decimal, decimal.Decimal) {
+	mid := quote.MidPrice()
+	if mid.IsZero() || !order.Side.Within(mid, order.Price) {
+		return decimal.Zero, decimal.Zero
+	}
+	return order.LeavesQty, mid
+}
+
+// OnTrade implements [FillModel].
+func (MidFill) OnTrade(*PaperOrder, *Trade) (decimal.Decimal, decimal.Decimal) {
+	return decimal.Zero, decimal.Zero
+}
+
+// QueueFill estimates the position of a passive order in the queue. An order
+// joining the near side at the best price is behind the size shown there; an
+// order improving on the best price is at the front. Trades at the limit
+// consume the queue ahead before filling the order, and trades through the
+// limit fill the whole order. An order crossed by the far side of a quote is
+// filled as [TouchFill].
+type QueueFill struct{}
+
+// OnQuote implements [FillModel].
+func (QueueFill) OnQuote(order *PaperOrder, quote *Quote) (decimal.Decimal, decimal.Decimal) {
+
+	if lastQty, lastPx := crossed(order, quote); lastQty.IsPositive() {
+		return lastQty, lastPx
+	}
+	if order.Price.IsZero() {
+		return decimal.Zero, decimal.Zero
+	}
+
+	nearPx, nearSize := quote.Near(order.Side)
+	switch {
+	case nearPx.Equal(order.Price):
+		if !order.queued || nearSize.LessThan(order.Ahead) {
+			order.Ahead = nearSize
+		}
+		order.queued = true
+	case !order.queued && (nearPx.IsZero() || order.Side.Within(nearPx, order.Price)):
+		//
+		// Better than the best price, or there is none.
+		//
+		order.Ahead = decimal.Zero
+		order.queued = true
+	}
+	return decimal.Zero, decimal.Zero
+
+}
+
+// OnTrade implements [FillModel].
+func (QueueFill) OnTrade(order *PaperOrder, trade *Trade) (decimal.Decimal, decimal.Decimal) {
+
+	if !order.queued {
+		return decimal.Zero, decimal.Zero
+	}
+
+	qty, px := traded(trade)
+	switch {
+	case px.Equal(order.Price):
+		if qty.LessThanOrEqual(order.Ahead) {
+			order.Ahead = order.Ahead.Sub(qty)
+			return decimal.Zero, decimal.Zero
+		}
+		qty = qty.Sub(order.Ahead)
+		order.Ahead = decimal.Zero
+		return qty, order.Price
+	case order.Side.Within(px, order.Price):
+		return order.LeavesQty, order.Price
+	default:
+		return decimal.Zero, decimal.Zero
+	}
+
+}
+
+// VolumeFill fills a fraction of the volume of each trade at or through the
+// limit, at the trade price.
+type VolumeFill struct {
+	Participation decimal.Decimal // For example 0.1 for 10%.
+}
+
+// OnQuote implements [FillModel].
+func (VolumeFill) OnQuote(*PaperOrder, *Quote) (decimal.Decimal, decimal.Decimal) {
+	return decimal.Zero, decimal.Zero
+}
+
+// OnTrade implements [FillModel].
+func (x VolumeFill) OnTrade(order *PaperOrder, trade *Trade) (decimal.Decimal, decimal.Decimal) {
+	qty, px := traded(trade)
+	if px.IsZero() || !order.Side.Within(px, order.Price) {
+		return decimal.Zero, decimal.Zero
+	}
+	return qty.Mul(x.Participation), px
+}
+
+// crossed returns the fill if the far side of the quote is at or through the
+// limit of the order.
+func crossed(order *PaperOrder, quote *Quote) (decimal.Decimal, decimal.Decimal) {
+	farPx, farSize := quote.Far(order.Side)
+	if farPx.IsZero() || !order.Side.Within(farPx, order.Price) {
+		return decimal.Zero, decimal.Zero
+	}
+	if farSize.IsZero() {
+		farSize = order.LeavesQty
+	}
+	return farSize, farPx
+}
+
+// traded returns the volume and price of a possibly aggregated trade.
+func traded(trade *Trade) (decimal.Decimal, decimal.Decimal) {
+	if trade.TradeVolume.IsZero() {
+		return trade.LastQty, trade.LastPx
+	}
+	return trade.TradeVolume, trade.AvgPx
+}
@@ -0,0 +1,228 @@
+package mkt
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPaper(model FillModel) (*Paper[*Listing], chan *Report, chan *PositionMemo) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{
+		Symbol:             "A",
+		TickIncrement:      decimal.New(1, 0),
+		RoundLot:           decimal.New(10, 0),
+		MinTradeVol:        decimal.New(10, 0),
+		ContractMultiplier: DecimalOne,
+	})
+
+	memos := make(chan *PositionMemo, 16)
+	reports := make(chan *Report, 16)
+
+	book := NewBook("PAPER", whitelist, WithBookChannel[*Listing](memos))
+	return NewPaper(book, model, WithPaperReports[*Listing](reports)), reports, memos
+
+}
+
+func testQuote(bid, bidSize, ask, askSize int64) *Quote {
+	return &Quote{
+		Symbol:  "A",
+		BidPx:   decimal.New(bid, 0),
+		BidSize: decimal.New(bidSize, 0),
+		AskPx:   decimal.New(ask, 0),
+		AskSize: decimal.New(askSize, 0),
+	}
+}
+
+func testTrade(qty, px int64) *Trade {
+	return &Trade{Symbol: "A", LastQty: decimal.New(qty, 0), LastPx: decimal.New(px, 0)}
+}
+
+func TestPaperTouchFill(t *testing.T) {
+
+	paper, reports, memos := newTestPaper(TouchFill{})
+
+	unknown := newTicket("X", Buy, 10, 42, GTC)
+	unknown.Symbol = "Z"
+	assert.NotNil(t, paper.Submit(unknown))
+	assert.Equal(t, OrdStatusRejected, (<-reports).OrdStatus)
+
+	paper.OnQuote(testQuote(41, 100, 43, 100))
+	assert.Nil(t, paper.Submit(newTicket("B1", Buy, 100, 42, GTC)))
+	assert.Equal(t, OrdStatusNew, (<-reports).OrdStatus)
+	assert.Equal(t, 0, len(reports))
+
+	paper.OnQuote(testQuote(41, 100, 42, 30))
+	report := <-reports
+	assert.Equal(t, OrdStatusPartiallyFilled, report.OrdStatus)
+	assert.True(t, report.LastQty.Equal(decimal.New(30, 0)))
+
+	paper.OnTrade(testTrade(5, 41))
+	report = <-reports
+	assert.Equal(t, OrdStatusFilled, report.OrdStatus)
+	assert.True(t, report.LastQty.Equal(decimal.New(70, 0)))
+	assert.True(t, report.LastPx.Equal(decimal.New(42, 0)))
+
+	assert.Equal(t, 2, len(memos))
+	<-memos
+	memo := <-memos
+	assert.True(t, memo.Quantity.Equal(decimal.New(100, 0)))
+	assert.True(t, memo.AvgPx.Equal(decimal.New(42, 0)))
+	assert.Equal(t, 0, len(paper.Working()))
+
+}
+
+func TestPaperMidFill(t *testing.T) {
+
+	paper, reports, _ := newTestPaper(MidFill{})
+
+	paper.OnQuote(testQuote(41, 100, 42, 100))
+	assert.Nil(t, paper.Submit(newTicket("S1", Sell, 20, 0, IOC)))
+	<-reports
+	report := <-reports
+	assert.Equal(t, OrdStatusFilled, report.OrdStatus)
+	assert.True(t, report.LastPx.Equal(decimal.New(415, -1)))
+
+	assert.Nil(t, paper.Submit(newTicket("S2", Sell, 20, 42, IOC)))
+	<-reports
+	assert.Equal(t, OrdStatusCanceled, (<-reports).OrdStatus)
+
+}
+
+func TestPaperQueueFill(t *testing.T) {
+
+	paper, reports, _ := newTestPaper(QueueFill{})
+
+	paper.OnQuote(testQuote(41, 50, 43, 100))
+	assert.Nil(t, paper.Submit(newTicket("B1", Buy, 20, 41, GTC)))
+	<-reports
+	assert.True(t, paper.Working()[0].Ahead.Equal(decimal.New(50, 0)))
+
+	paper.OnQuote(testQuote(41, 30, 43, 100))
+	assert.True(t, paper.Working()[0].Ahead.Equal(decimal.New(30, 0)), "cancellations ahead")
+
+	paper.OnTrade(testTrade(20, 41))
+	assert.Equal(t, 0, len(reports))
+
+	paper.OnTrade(testTrade(20, 41))
+	report := <-reports
+	assert.Equal(t, OrdStatusPartiallyFilled, report.OrdStatus)
+	assert.True(t, report.LastQty.Equal(decimal.New(10, 0)))
+
+	paper.OnTrade(testTrade(10, 40))
+	report = <-reports
+	assert.Equal(t, OrdStatusFilled, report.OrdStatus)
+	assert.True(t, report.LastPx.Equal(decimal.New(41, 0)))
+
+}
+
+func TestPaperQueueFillAway(t *testing.T) {
+
+	paper, reports, _ := newTestPaper(QueueFill{})
+
+	//
+	// Behind the best price the order is not in the queue, so a trade at its
+	// limit does not fill it.
+	//
+	paper.OnQuote(testQuote(43, 50, 45, 100))
+	assert.Nil(t, paper.Submit(newTicket("B1", Buy, 20, 42, GTC)))
+	<-reports
+	paper.OnTrade(testTrade(20, 42))
+	assert.Equal(t, 0, len(reports))
+
+	//
+	// Improving on the best price the order is at the front of the queue.
+	//
+	assert.Nil(t, paper.Submit(newTicket("B2", Buy, 20, 44, GTC)))
+	<-reports
+	assert.True(t, paper.Working()[1].Ahead.IsZero())
+	paper.OnTrade(testTrade(20, 44))
+	report := <-reports
+	assert.Equal(t, "B2", report.OrderID)
+	assert.Equal(t, OrdStatusFilled, report.OrdStatus)
+
+	//
+	// So is an order on an empty side.
+	//
+	paper.OnQuote(&Quote{Symbol: "A", AskPx: decimal.New(45, 0), AskSize: decimal.New(100, 0)})
+	paper.OnTrade(testTrade(20, 42))
+	report = <-reports
+	assert.Equal(t, "B1", report.OrderID)
+	assert.Equal(t, OrdStatusFilled, report.OrdStatus)
+
+}
+
+func TestPaperVolumeFill(t *testing.T) {
+
+	paper, reports, _ := newTestPaper(VolumeFill{Participation: decimal.New(1, -1)})
+
+	assert.Nil(t, paper.Submit(newTicket("B1", Buy, 100, 42, GTC)))
+	<-reports
+
+	paper.OnTrade(testTrade(50, 42))
+	assert.Equal(t, 0, len(reports), "less than a round lot")
+
+	paper.OnTrade(testTrade(200, 43))
+	assert.Equal(t, 0, len(reports), "outside the limit")
+
+	paper.OnTrade(testTrade(200, 41))
+	report := <-reports
+	assert.True(t, report.LastQty.Equal(decimal.New(20, 0)))
+	assert.True(t, report.LastPx.Equal(decimal.New(41, 0)))
+
+	replace := newTicket("B1", Buy, 10, 42, GTC)
+	replace.MsgType = OrderReplace
+	assert.NotNil(t, paper.Submit(replace))
+
+	cancel := newTicket("B1", Buy, 100, 42, GTC)
+	cancel.MsgType = OrderCancel
+	assert.Nil(t, paper.Submit(cancel))
+	assert.Equal(t, OrdStatusCanceled, (<-reports).OrdStatus)
+
+}
+
+func TestPaperReplace(t *testing.T) {
+
+	paper, reports, _ := newTestPaper(TouchFill{})
+
+	paper.OnQuote(testQuote(41, 100, 43, 30))
+	assert.Nil(t, paper.Submit(newTicket("B1", Buy, 100, 42, GTC)))
+	assert.Equal(t, OrdStatusNew, (<-reports).OrdStatus)
+
+	//
+	// The replaced order is evaluated against the last quote, and the
+	// remainder canceled as it is now IOC.
+	//
+	replace := newTicket("B1", Buy, 100, 43, IOC)
+	replace.MsgType = OrderReplace
+	assert.Nil(t, paper.Submit(replace))
+	report := <-reports
+	assert.Equal(t, OrdStatusNew, report.OrdStatus)
+	assert.Equal(t, IOC, report.TimeInForce)
+	report = <-reports
+	assert.Equal(t, OrdStatusPartiallyFilled, report.OrdStatus)
+	assert.True(t, report.LastQty.Equal(decimal.New(30, 0)))
+	assert.True(t, report.LastPx.Equal(decimal.New(43, 0)))
+	assert.Equal(t, OrdStatusCanceled, (<-reports).OrdStatus)
+	assert.Equal(t, 0, len(paper.Working()))
+
+}
+
+func TestPaperDelisted(t *testing.T) {
+
+	paper, reports, memos := newTestPaper(TouchFill{})
+
+	assert.Nil(t, paper.Submit(newTicket("B1", Buy, 100, 42, GTC)))
+	assert.Equal(t, OrdStatusNew, (<-reports).OrdStatus)
+
+	paper.Book().whitelist.Remove("A")
+	paper.OnQuote(testQuote(41, 100, 42, 30))
+	report := <-reports
+	assert.Equal(t, OrdStatusCanceled, report.OrdStatus)
+	assert.True(t, report.LastQty.IsZero())
+	assert.Equal(t, 0, len(paper.Working()))
+	assert.Equal(t, 0, len(memos))
+
+}
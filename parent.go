@@ -0,0 +1,331 @@
+package mkt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Parent is an order worked by an execution algorithm as child orders sent to
+// venues. The [Ticket] holds the total quantity, the limit price, if any, and
+// the time in force.
+//
+// Every child order is submitted through [Parent.Submit] before it is sent,
+// which refuses any child that would take the live child quantity above the
+// parent leaves quantity. Reports for the children are given to
+// [Parent.OnReport], which rolls them up into the parent CumQty, AvgPx and
+// status.
+//
+// A child being replaced counts as the larger of its old and new leaves
+// quantity until the replacement is reported, and a child being canceled
+// counts in full until the cancel is reported. Parent is not safe for
+// concurrent use.
+type Parent[T AnyListing] struct {
+	Ticket
+	CumQty    decimal.Decimal
+	AvgPx     decimal.Decimal
+	listing   T
+	precision int32             // Of AvgPx.
+	children  []*Child          // In submission sequence.
+	byID      map[string]*Child //
+	canceled  bool              // No more children.
+}
+
+// Child is a child order of a [Parent].
+type Child struct {
+	Ticket
+	CumQty    decimal.Decimal
+	AvgPx     decimal.Decimal
+	OrdStatus OrdStatus
+	replace   *Ticket // Awaiting a report.
+}
+
+// NewParent returns a [*Parent] for the ticket, which must be a new order in
+// the listing's symbol with a positive quantity.
+func NewParent[T AnyListing](listing T, ticket *Ticket) (*Parent[T], error) {
+
+	if ticket == nil {
+		return nil, errors.New("mkt.Parent: nil ticket")
+	}
+	def := listing.Definition()
+	if ticket.Symbol != def.Symbol {
+		return nil, fmt.Errorf("mkt.Parent: symbol %s is not %s", ticket.Symbol, def.Symbol)
+	}
+	if ticket.Side != Buy && ticket.Side != Sell {
+		return nil, errors.New("mkt.Parent: no side")
+	}
+	if !ticket.OrderQty.IsPositive() {
+		return nil, fmt.Errorf("mkt.Parent: OrderQty %s is not positive", ticket.OrderQty)
+	}
+
+	return &Parent[T]{
+		Ticket:    *ticket,
+		listing:   listing,
+		precision: def.PricePrecision() + 1,
+		byID:      map[string]*Child{},
+	}, nil
+
+}
+
+// Listing returns the listing of the parent.
+func (x *Parent[T]) Listing() T { return x.listing }
+
+// LeavesQty returns the quantity still to be done, which is zero once the
+// parent is done.
+func (x *Parent[T]) LeavesQty() decimal.Decimal {
+	if x.canceled {
+		return decimal.Zero
+	}
+	return decimal.Max(x.OrderQty.Sub(x.CumQty), decimal.Zero)
+}
+
+// LiveQty returns the quantity working in live child orders.
+func (x *Parent[T]) LiveQty() decimal.Decimal {
+	live := decimal.Zero
+	for _, child := range x.children {
+		live = live.Add(child.LeavesQty())
+	}
+	return live
+}
+
+// AvailableQty returns the quantity that may yet be sent in child orders.
+func (x *Parent[T]) AvailableQty() decimal.Decimal {
+	return decimal.Max(x.LeavesQty().Sub(x.LiveQty()), decimal.Zero)
+}
+
+// OrdStatus returns the status of the parent rolled up from its children.
+func (x *Parent[T]) OrdStatus() OrdStatus {
+
+	if !x.CumQty.LessThan(x.OrderQty) {
+		return OrdStatusFilled
+	}
+	if x.canceled {
+		if x.LiveQty().IsPositive() {
+			return OrdStatusPendingCancel
+		}
+		return OrdStatusCanceled
+	}
+	if x.CumQty.IsPositive() {
+		return OrdStatusPartiallyFilled
+	}
+	return OrdStatusNew
+
+}
+
+// Children returns the child orders, in submission sequence.
+func (x *Parent[T]) Children() []*Child {
+	return append([]*Child(nil), x.children...)
+}
+
+// Live returns the child orders that are still working.
+func (x *Parent[T]) Live() []*Child {
+	var live []*Child
+	for _, child := range x.children {
+		if child.Live() {
+			live = append(live, child)
+		}
+	}
+	return live
+}
+
+// Cancel stops the parent from sending any more child orders. The live child
+// orders are returned so that the caller may cancel them; the parent is
+// canceled once they are done.
+func (x *Parent[T]) Cancel() []*Child {
+	x.canceled = true
+	return x.Live()
+}
+
+// Submit records a child order, cancel or replace according to
+// [Ticket.MsgType], returning an error if it is refused. A new child order
+// must have a unique OrderID, the symbol and side of the parent, a price
+// within the parent limit and a time in force no longer than the parent's.
+// Neither a new child nor a replace may take the live child quantity above
+// the parent leaves quantity.
+func (x *Parent[T]) Submit(ticket *Ticket) error {
+
+	if ticket == nil {
+		return errors.New("mkt.Parent: nil ticket")
+	}
+
+	switch ticket.MsgType {
+
+	case OrderNew:
+		if ticket.OrderID == "" {
+			return errors.New("mkt.Parent: no OrderID")
+		}
+		if _, ok := x.byID[ticket.OrderID]; ok {
+			return fmt.Errorf("mkt.Parent: duplicate OrderID %s", ticket.OrderID)
+		}
+		if ticket.Symbol != x.Symbol || ticket.Side != x.Side {
+			return fmt.Errorf("mkt.Parent: child %s %s is not %s %s", ticket.Side, ticket.Symbol, x.Side, x.Symbol)
+		}
+		if err := x.validate(ticket); err != nil {
+			return err
+		}
+		if !ticket.OrderQty.IsPositive() {
+			return fmt.Errorf("mkt.Parent: OrderQty %s is not positive", ticket.OrderQty)
+		}
+		if available := x.AvailableQty(); ticket.OrderQty.GreaterThan(available) {
+			return fmt.Errorf("mkt.Parent: OrderQty %s exceeds the available %s", ticket.OrderQty, available)
+		}
+		child := &Child{Ticket: *ticket, OrdStatus: OrdStatusPendingNew}
+		x.children = append(x.children, child)
+		x.byID[child.OrderID] = child
+		return nil
+
+	case OrderCancel:
+		child, err := x.find(ticket.OrderID)
+		if err != nil {
+			return err
+		}
+		child.OrdStatus = OrdStatusPendingCancel
+		return nil
+
+	case OrderReplace:
+		child, err := x.find(ticket.OrderID)
+		if err != nil {
+			return err
+		}
+		if err := x.validate(ticket); err != nil {
+			return err
+		}
+		leavesQty := ticket.OrderQty.Sub(child.CumQty)
+		if !leavesQty.IsPositive() {
+			return fmt.Errorf("mkt.Parent: OrderQty %s is not above CumQty %s", ticket.OrderQty, child.CumQty)
+		}
+		//
+		// Only an increase in the leaves quantity of the child needs to be
+		// available.
+		//
+		increase := leavesQty.Sub(child.LeavesQty())
+		if available := x.AvailableQty(); increase.GreaterThan(available) {
+			return fmt.Errorf("mkt.Parent: increase %s exceeds the available %s", increase, available)
+		}
+		replace := *ticket
+		replace.Symbol, replace.Side = child.Symbol, child.Side
+		child.replace = &replace
+		return nil
+
+	default:
+		return fmt.Errorf("mkt.Parent: unsupported MsgType %d", ticket.MsgType)
+	}
+
+}
+
+// OnReport rolls up a report for one of the child orders. A fill is added to
+// the CumQty and AvgPx of both the child and the parent. A pending replace is
+// applied by a report with [ExecTypeReplaced] and dropped by a report with
+// [ExecTypeRejected].
+func (x *Parent[T]) OnReport(report *Report) error {
+
+	if report == nil {
+		return errors.New("mkt.Parent: nil report")
+	}
+	child, ok := x.byID[report.OrderID]
+	if !ok {
+		return fmt.Errorf("mkt.Parent: unknown OrderID %s", report.OrderID)
+	}
+
+	if report.LastQty.IsPositive() {
+		child.CumQty, child.AvgPx = CumQtyAvgPx(child.CumQty, child.AvgPx, report.LastQty, report.LastPx, x.precision)
+		x.CumQty, x.AvgPx = CumQtyAvgPx(x.CumQty, x.AvgPx, report.LastQty, report.LastPx, x.precision)
+	}
+
+	if child.replace != nil {
+		switch report.ExecType {
+		case ExecTypeReplaced:
+			child.replaced()
+		case ExecTypeRejected:
+			child.replace = nil
+		}
+	}
+
+	if report.OrdStatus != 0 {
+		child.OrdStatus = report.OrdStatus
+	}
+	if !child.Live() {
+		child.replace = nil
+	}
+	return nil
+
+}
+
+// validate the price and time in force of a child against the parent.
+func (x *Parent[T]) validate(ticket *Ticket) error {
+
+	if !x.Price.IsZero() {
+		if ticket.Price.IsZero() {
+			return fmt.Errorf("mkt.Parent: market order outside the limit %s", x.Price)
+		}
+		if !x.Side.Within(ticket.Price, x.Price) {
+			return fmt.Errorf("mkt.Parent: price %s outside the limit %s", ticket.Price, x.Price)
+		}
+	}
+
+	//
+	// A day order is the zero value, so ranks between IOC and GTC.
+	//
+	rank := func(tif TimeInForce) int {
+		switch tif {
+		case IOC:
+			return 0
+		case GTC:
+			return 2
+		default:
+			return 1
+		}
+	}
+	if rank(ticket.TimeInForce) > rank(x.TimeInForce) {
+		return fmt.Errorf("mkt.Parent: time in force %s outlasts %s", ticket.TimeInForce, x.TimeInForce)
+	}
+	return nil
+
+}
+
+// find returns the live child with the OrderID.
+func (x *Parent[T]) find(orderID string) (*Child, error) {
+	child, ok := x.byID[orderID]
+	if !ok {
+		return nil, fmt.Errorf("mkt.Parent: unknown OrderID %s", orderID)
+	}
+	if !child.Live() {
+		return nil, fmt.Errorf("mkt.Parent: OrderID %s is done", orderID)
+	}
+	return child, nil
+}
+
+// Live returns true until the child is filled, canceled, rejected or expired.
+func (x *Child) Live() bool {
+	switch x.OrdStatus {
+	case OrdStatusFilled, OrdStatusCanceled, OrdStatusRejected, OrdStatusExpired:
+		return false
+	default:
+		return true
+	}
+}
+
+// LeavesQty returns the quantity the child may yet fill, which is zero once it
+// is done. While a replace is pending it is the larger of the old and new
+// leaves quantity.
+func (x *Child) LeavesQty() decimal.Decimal {
+	if !x.Live() {
+		return decimal.Zero
+	}
+	leavesQty := x.OrderQty.Sub(x.CumQty)
+	if x.replace != nil {
+		leavesQty = decimal.Max(leavesQty, x.replace.OrderQty.Sub(x.CumQty))
+	}
+	return decimal.Max(leavesQty, decimal.Zero)
+}
+
+// Pending returns true if a replace is awaiting a report.
+func (x *Child) Pending() bool {
+	return x.replace != nil
+}
+
+func (x *Child) replaced() {
+	x.OrderQty, x.Price, x.TimeInForce = x.replace.OrderQty, x.replace.Price, x.replace.TimeInForce
+	x.replace = nil
+}
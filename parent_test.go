@@ -0,0 +1,135 @@
+package mkt
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testChild(msgType MsgType, orderID string, qty, px int64, tif TimeInForce) *Ticket {
+	return &Ticket{
+		Order:       Order{MsgType: msgType, OrderID: orderID, Side: Buy, Symbol: "A"},
+		OrderQty:    decimal.New(qty, 0),
+		Price:       decimal.New(px, 0),
+		TimeInForce: tif,
+	}
+}
+
+func TestParentNew(t *testing.T) {
+
+	listing := &Listing{Symbol: "A", TickIncrement: decimal.New(1, -2)}
+
+	_, err := NewParent(listing, nil)
+	assert.NotNil(t, err)
+	_, err = NewParent(listing, testChild(OrderNew, "P", 0, 100, GTC))
+	assert.NotNil(t, err)
+	wrong := testChild(OrderNew, "P", 10, 100, GTC)
+	wrong.Symbol = "B"
+	_, err = NewParent(listing, wrong)
+	assert.NotNil(t, err)
+
+	parent, err := NewParent(listing, testChild(OrderNew, "P", 10, 100, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, OrdStatusNew, parent.OrdStatus())
+	assert.True(t, parent.AvailableQty().Equal(decimal.New(10, 0)))
+
+	bad := testChild(OrderNew, "C", 1, 100, 0)
+	bad.Side = Sell
+	assert.NotNil(t, parent.Submit(bad))
+	assert.NotNil(t, parent.Submit(testChild(OrderNew, "", 1, 100, 0)))
+	assert.NotNil(t, parent.Submit(testChild(OrderNew, "C", 1, 101, 0)))
+	assert.NotNil(t, parent.Submit(testChild(OrderNew, "C", 1, 0, 0)))
+	assert.NotNil(t, parent.Submit(testChild(OrderNew, "C", 1, 99, GTC)))
+	assert.NotNil(t, parent.Submit(testChild(OrderNew, "C", 11, 99, 0)))
+	assert.NotNil(t, parent.Submit(testChild(OrderNew, "C", 0, 99, 0)))
+	assert.NotNil(t, parent.Submit(testChild(0, "C", 1, 99, 0)))
+	assert.Equal(t, 0, len(parent.Children()))
+
+	assert.Nil(t, parent.Submit(testChild(OrderNew, "C1", 6, 99, IOC)))
+	assert.NotNil(t, parent.Submit(testChild(OrderNew, "C1", 1, 99, 0)))
+	assert.Nil(t, parent.Submit(testChild(OrderNew, "C2", 4, 100, 0)))
+	assert.True(t, parent.AvailableQty().IsZero())
+	assert.NotNil(t, parent.Submit(testChild(OrderNew, "C3", 1, 100, 0)))
+	assert.Equal(t, 2, len(parent.Live()))
+
+}
+
+func TestParentRollUp(t *testing.T) {
+
+	listing := &Listing{Symbol: "A", TickIncrement: decimal.New(1, -2)}
+	parent, err := NewParent(listing, testChild(OrderNew, "P", 10, 0, GTC))
+	assert.Nil(t, err)
+
+	assert.Nil(t, parent.Submit(testChild(OrderNew, "C1", 4, 100, GTC)))
+	assert.Nil(t, parent.Submit(testChild(OrderNew, "C2", 4, 0, 0)))
+	assert.NotNil(t, parent.OnReport(&Report{OrderID: "Z"}))
+
+	assert.Nil(t, parent.OnReport(&Report{OrderID: "C1", OrdStatus: OrdStatusNew}))
+	assert.Nil(t, parent.OnReport(&Report{OrderID: "C1", OrdStatus: OrdStatusPartiallyFilled, LastQty: decimal.New(1, 0), LastPx: decimal.New(100, 0)}))
+	assert.Nil(t, parent.OnReport(&Report{OrderID: "C2", OrdStatus: OrdStatusFilled, LastQty: decimal.New(4, 0), LastPx: decimal.New(101, 0)}))
+	assert.Equal(t, OrdStatusPartiallyFilled, parent.OrdStatus())
+	assert.True(t, parent.CumQty.Equal(decimal.New(5, 0)))
+	assert.True(t, parent.AvgPx.Equal(decimal.RequireFromString("100.8")))
+	assert.True(t, parent.LiveQty().Equal(decimal.New(3, 0)))
+	assert.True(t, parent.AvailableQty().Equal(decimal.New(2, 0)))
+
+	//
+	// A replace up counts at the new quantity until reported, while a replace
+	// down counts at the old.
+	//
+	assert.NotNil(t, parent.Submit(testChild(OrderReplace, "C2", 5, 100, GTC)))
+	assert.NotNil(t, parent.Submit(testChild(OrderReplace, "C1", 1, 100, GTC)))
+	assert.NotNil(t, parent.Submit(testChild(OrderReplace, "C1", 7, 100, GTC)))
+	assert.Nil(t, parent.Submit(testChild(OrderReplace, "C1", 6, 100, GTC)))
+	assert.True(t, parent.AvailableQty().IsZero())
+	assert.True(t, parent.Children()[0].Pending())
+	assert.Nil(t, parent.OnReport(&Report{OrderID: "C1", OrdStatus: OrdStatusPartiallyFilled, ExecType: ExecTypeReplaced}))
+	assert.False(t, parent.Children()[0].Pending())
+	assert.True(t, parent.Children()[0].OrderQty.Equal(decimal.New(6, 0)))
+
+	//
+	// Only a replaced report applies the replace, not a late report of the
+	// original order, and a reject drops it.
+	//
+	assert.Nil(t, parent.Submit(testChild(OrderReplace, "C1", 3, 100, GTC)))
+	assert.True(t, parent.AvailableQty().IsZero())
+	assert.Nil(t, parent.OnReport(&Report{OrderID: "C1", OrdStatus: OrdStatusPartiallyFilled}))
+	assert.True(t, parent.Children()[0].Pending())
+	assert.Nil(t, parent.OnReport(&Report{OrderID: "C1", OrdStatus: OrdStatusPartiallyFilled, ExecType: ExecTypeRejected}))
+	assert.False(t, parent.Children()[0].Pending())
+	assert.True(t, parent.Children()[0].OrderQty.Equal(decimal.New(6, 0)))
+	assert.Equal(t, OrdStatusPartiallyFilled, parent.Children()[0].OrdStatus)
+
+	assert.Nil(t, parent.Submit(testChild(OrderReplace, "C1", 3, 100, GTC)))
+	assert.Nil(t, parent.OnReport(&Report{OrderID: "C1", OrdStatus: OrdStatusPartiallyFilled, ExecType: ExecTypeReplaced}))
+	assert.True(t, parent.AvailableQty().Equal(decimal.New(3, 0)))
+
+	//
+	// Canceling the parent leaves it pending until the live child is done.
+	//
+	live := parent.Cancel()
+	assert.Equal(t, 1, len(live))
+	assert.True(t, parent.AvailableQty().IsZero())
+	assert.Equal(t, OrdStatusPendingCancel, parent.OrdStatus())
+	assert.Nil(t, parent.Submit(testChild(OrderCancel, "C1", 0, 0, 0)))
+	assert.Equal(t, OrdStatusPendingCancel, parent.Children()[0].OrdStatus)
+	assert.Nil(t, parent.OnReport(&Report{OrderID: "C1", OrdStatus: OrdStatusCanceled}))
+	assert.Equal(t, OrdStatusCanceled, parent.OrdStatus())
+	assert.NotNil(t, parent.Submit(testChild(OrderCancel, "C1", 0, 0, 0)))
+	assert.True(t, parent.LiveQty().IsZero())
+
+}
+
+func TestParentFilled(t *testing.T) {
+
+	listing := &Listing{Symbol: "A"}
+	parent, err := NewParent(listing, testChild(OrderNew, "P", 2, 0, 0))
+	assert.Nil(t, err)
+	assert.Nil(t, parent.Submit(testChild(OrderNew, "C1", 2, 0, 0)))
+	assert.Nil(t, parent.OnReport(&Report{OrderID: "C1", OrdStatus: OrdStatusFilled, LastQty: decimal.New(2, 0), LastPx: decimal.New(5, 0)}))
+	assert.Equal(t, OrdStatusFilled, parent.OrdStatus())
+	assert.True(t, parent.LeavesQty().IsZero())
+	assert.Equal(t, 0, len(parent.Live()))
+
+}
@@ -0,0 +1,277 @@
+package mkt
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Participation sizes and prices child orders so that our fills are a target
+// fraction of the volume traded in the market, known as percentage of volume.
+//
+// Market volume is counted from each [Trade] given to [Participation.OnTrade],
+// using the TradeVolume if it is set, otherwise the LastQty, and is taken to
+// include our own fills as they print on the tape. Our fills are counted from
+// each [Report] given to [Participation.OnReport], less any that are busted
+// or corrected by a report with the ExecRefID of the fill. A bust or
+// correction that arrives before its fill is held until the fill arrives.
+//
+// [Participation.Child] returns the quantity that would bring participation
+// back to the target, less what is already working. While participation is
+// below the lower bound of the band the child takes liquidity at the far
+// price, otherwise it joins the near price. Above the target no child is
+// needed, and above the upper bound [Participation.Excess] is the working
+// quantity to cancel. Participation is not safe for concurrent use.
+type Participation[T AnyListing] struct {
+	listing  T
+	side     Side
+	target   decimal.Decimal
+	lower    decimal.Decimal
+	upper    decimal.Decimal
+	limit    decimal.Decimal // Zero for none.
+	orderQty decimal.Decimal // Zero for unlimited.
+	minClip  decimal.Decimal
+	maxClip  decimal.Decimal            // Zero for unlimited.
+	volume   decimal.Decimal            // Market volume.
+	filled   decimal.Decimal            // Our volume.
+	seen     map[string]bool            // ExecIDs already counted.
+	fills    map[string]decimal.Decimal // Quantity counted, by ExecID.
+	pending  map[string][]*Report       // Busts and corrections by ExecRefID.
+}
+
+// ParticipationOption is any option that can be applied when constructing
+// [Participation].
+type ParticipationOption[T AnyListing] func(*Participation[T])
+
+// WithParticipationBand sets the band around the target. The default is no
+// band, so that any shortfall is taken from the far side.
+func WithParticipationBand[T AnyListing](lower, upper decimal.Decimal) ParticipationOption[T] {
+	return func(x *Participation[T]) {
+		x.lower, x.upper = lower, upper
+	}
+}
+
+// WithParticipationLimit sets the limit price. The default of zero is no limit.
+func WithParticipationLimit[T AnyListing](limit decimal.Decimal) ParticipationOption[T] {
+	return func(x *Participation[T]) {
+		x.limit = limit
+	}
+}
+
+// WithParticipationQty sets the total quantity to fill. The default of zero is
+// no total.
+func WithParticipationQty[T AnyListing](orderQty decimal.Decimal) ParticipationOption[T] {
+	return func(x *Participation[T]) {
+		x.orderQty = orderQty
+	}
+}
+
+// WithParticipationClip sets the minimum and maximum child quantity. A
+// shortfall below the minimum waits for more market volume. A zero maximum is
+// no maximum.
+func WithParticipationClip[T AnyListing](minClip, maxClip decimal.Decimal) ParticipationOption[T] {
+	return func(x *Participation[T]) {
+		x.minClip, x.maxClip = minClip, maxClip
+	}
+}
+
+// NewParticipation returns a [*Participation] trading the side of the listing
+// at the target fraction of market volume, which must be between zero and
+// one. The band, if set, must contain the target.
+func NewParticipation[T AnyListing](listing T, side Side, target decimal.Decimal, options ...ParticipationOption[T]) (*Participation[T], error) {
+
+	if side != Buy && side != Sell {
+		return nil, fmt.Errorf("mkt.Participation: unknown side %d", side)
+	}
+	if !target.IsPositive() || !target.LessThan(DecimalOne) {
+		return nil, fmt.Errorf("mkt.Participation: target %s is not between zero and one", target)
+	}
+
+	participation := &Participation[T]{
+		listing: listing,
+		side:    side,
+		target:  target,
+		lower:   target,
+		upper:   target,
+		seen:    map[string]bool{},
+		fills:   map[string]decimal.Decimal{},
+		pending: map[string][]*Report{},
+	}
+	for _, option := range options {
+		option(participation)
+	}
+
+	if participation.lower.GreaterThan(target) || participation.upper.LessThan(target) {
+		return nil, fmt.Errorf("mkt.Participation: band %s to %s does not contain %s", participation.lower, participation.upper, target)
+	}
+	if participation.maxClip.IsPositive() && participation.maxClip.LessThan(participation.minClip) {
+		return nil, fmt.Errorf("mkt.Participation: maximum clip %s is below the minimum %s", participation.maxClip, participation.minClip)
+	}
+	return participation, nil
+
+}
+
+// OnTrade adds the volume of a trade in the listing to the market volume.
+func (x *Participation[T]) OnTrade(trade *Trade) {
+	if trade == nil || trade.Symbol != x.listing.Definition().Symbol {
+		return
+	}
+	if trade.TradeVolume.IsPositive() {
+		x.volume = x.volume.Add(trade.TradeVolume)
+		return
+	}
+	x.volume = x.volume.Add(decimal.Max(trade.LastQty, decimal.Zero))
+}
+
+// OnReport adds a fill to our volume. A bust, ExecType H, takes the fill named
+// by its ExecRefID out of our volume and a correction, ExecType G, replaces
+// its quantity with the LastQty of the correction. A report with the same
+// ExecID as one already counted is ignored, as are other reports. The report
+// is copied if it is held, so may be recycled once this function returns.
+func (x *Participation[T]) OnReport(report *Report) {
+	if report == nil {
+		return
+	}
+	if report.Symbol != "" && report.Symbol != x.listing.Definition().Symbol {
+		return
+	}
+	switch report.ExecType {
+	case 0, ExecTypeTrade:
+		if !report.LastQty.IsPositive() {
+			return
+		}
+	case ExecTypeTradeCancel, ExecTypeTradeCorrect:
+		if report.ExecRefID == "" {
+			return
+		}
+	default:
+		return
+	}
+	if report.ExecID != "" {
+		if x.seen[report.ExecID] {
+			return
+		}
+		x.seen[report.ExecID] = true
+	}
+
+	if report.ExecType == ExecTypeTradeCancel || report.ExecType == ExecTypeTradeCorrect {
+		if _, ok := x.fills[report.ExecRefID]; !ok {
+			copied := *report
+			x.pending[report.ExecRefID] = append(x.pending[report.ExecRefID], &copied)
+			return
+		}
+		x.amend(report)
+		return
+	}
+
+	x.filled = x.filled.Add(report.LastQty)
+	if report.ExecID == "" {
+		return
+	}
+	x.fills[report.ExecID] = report.LastQty
+	waiting := x.pending[report.ExecID]
+	delete(x.pending, report.ExecID)
+	for _, amendment := range waiting {
+		if _, ok := x.fills[report.ExecID]; !ok {
+			break // Busted already.
+		}
+		x.amend(amendment)
+	}
+}
+
+// amend busts or corrects the fill named by the ExecRefID.
+func (x *Participation[T]) amend(amendment *Report) {
+	x.filled = x.filled.Sub(x.fills[amendment.ExecRefID])
+	if amendment.ExecType == ExecTypeTradeCancel {
+		delete(x.fills, amendment.ExecRefID)
+		return
+	}
+	lastQty := decimal.Max(amendment.LastQty, decimal.Zero)
+	x.filled = x.filled.Add(lastQty)
+	x.fills[amendment.ExecRefID] = lastQty
+}
+
+// Volume returns the market volume.
+func (x *Participation[T]) Volume() decimal.Decimal { return x.volume }
+
+// Filled returns our volume.
+func (x *Participation[T]) Filled() decimal.Decimal { return x.filled }
+
+// Rate returns our volume as a fraction of the market volume, or zero if there
+// is no market volume.
+func (x *Participation[T]) Rate() decimal.Decimal {
+	if !x.volume.IsPositive() {
+		return decimal.Zero
+	}
+	return x.filled.Div(x.volume)
+}
+
+// Child returns the quantity and price of the next child order given the
+// quote and the quantity already working, such as [Parent.LiveQty]. A zero
+// quantity means no child is needed now.
+//
+// The quantity is clipped, limited to any total quantity left and rounded
+// down to whole lots of the listing with [Units]. The price is never outside
+// the limit: if the far price is, the near price is used, and if that is too,
+// the limit itself.
+func (x *Participation[T]) Child(quote *Quote, working decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+
+	qty := x.shortfall().Sub(working)
+	if x.orderQty.IsPositive() {
+		qty = decimal.Min(qty, x.orderQty.Sub(x.filled).Sub(working))
+	}
+	if !qty.IsPositive() || qty.LessThan(x.minClip) {
+		return decimal.Zero, decimal.Zero
+	}
+	if x.maxClip.IsPositive() {
+		qty = decimal.Min(qty, x.maxClip)
+	}
+	def := x.listing.Definition()
+	qty = Units(qty, def.RoundLot, def.MinTradeVol)
+	if qty.IsZero() {
+		return decimal.Zero, decimal.Zero
+	}
+
+	price := x.price(quote)
+	if price.IsZero() {
+		return decimal.Zero, decimal.Zero
+	}
+	return qty, price
+
+}
+
+// Excess returns the quantity of the working child orders to cancel while
+// participation is above the upper bound of the band, or zero.
+func (x *Participation[T]) Excess(working decimal.Decimal) decimal.Decimal {
+	if !x.volume.IsPositive() || !x.Rate().GreaterThan(x.upper) {
+		return decimal.Zero
+	}
+	return decimal.Max(working.Sub(decimal.Max(x.shortfall(), decimal.Zero)), decimal.Zero)
+}
+
+// shortfall returns the quantity to fill to bring participation to the
+// target, which is negative when ahead. Filling q more takes participation to
+// the target when (filled + q) / (volume + q) = target.
+func (x *Participation[T]) shortfall() decimal.Decimal {
+	return x.target.Mul(x.volume).Sub(x.filled).Div(DecimalOne.Sub(x.target))
+}
+
+// price returns the far price while behind the band, otherwise the near
+// price, within the limit. It returns zero if there is no price.
+func (x *Participation[T]) price(quote *Quote) decimal.Decimal {
+
+	near, _ := quote.Near(x.side)
+	far, _ := quote.Far(x.side)
+
+	candidates := []decimal.Decimal{near, x.limit}
+	if x.Rate().LessThan(x.lower) {
+		candidates = append([]decimal.Decimal{far}, candidates...)
+	}
+	for _, price := range candidates {
+		if price.IsPositive() && x.side.Within(price, x.limit) {
+			return price
+		}
+	}
+	return decimal.Zero
+
+}
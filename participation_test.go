@@ -0,0 +1,155 @@
+package mkt
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParticipationNew(t *testing.T) {
+
+	listing := &Listing{Symbol: "A"}
+	target := decimal.RequireFromString("0.2")
+
+	_, err := NewParticipation(listing, 0, target)
+	assert.NotNil(t, err)
+	_, err = NewParticipation(listing, Buy, decimal.Zero)
+	assert.NotNil(t, err)
+	_, err = NewParticipation(listing, Buy, DecimalOne)
+	assert.NotNil(t, err)
+	_, err = NewParticipation(listing, Buy, target, WithParticipationBand[*Listing](decimal.RequireFromString("0.25"), decimal.RequireFromString("0.3")))
+	assert.NotNil(t, err)
+	_, err = NewParticipation(listing, Buy, target, WithParticipationClip[*Listing](decimal.New(10, 0), decimal.New(5, 0)))
+	assert.NotNil(t, err)
+	_, err = NewParticipation(listing, Buy, target, WithParticipationClip[*Listing](decimal.New(10, 0), decimal.Zero))
+	assert.Nil(t, err)
+
+}
+
+func TestParticipation(t *testing.T) {
+
+	listing := &Listing{Symbol: "A", RoundLot: decimal.New(10, 0), MinTradeVol: decimal.New(10, 0)}
+	pov, err := NewParticipation(listing, Buy, decimal.RequireFromString("0.2"),
+		WithParticipationBand[*Listing](decimal.RequireFromString("0.1"), decimal.RequireFromString("0.3")),
+		WithParticipationLimit[*Listing](decimal.New(101, 0)),
+		WithParticipationClip[*Listing](decimal.New(10, 0), decimal.New(100, 0)),
+	)
+	assert.Nil(t, err)
+	quote := &Quote{Symbol: "A", BidPx: decimal.New(99, 0), BidSize: DecimalOne, AskPx: decimal.New(100, 0), AskSize: DecimalOne}
+
+	qty, _ := pov.Child(quote, decimal.Zero)
+	assert.True(t, qty.IsZero())
+
+	//
+	// Behind the band the child takes the far price.
+	//
+	pov.OnTrade(&Trade{Symbol: "A", LastQty: decimal.New(400, 0), LastPx: decimal.New(100, 0)})
+	pov.OnTrade(&Trade{Symbol: "B", LastQty: decimal.New(400, 0), LastPx: decimal.New(100, 0)})
+	assert.True(t, pov.Volume().Equal(decimal.New(400, 0)))
+	qty, px := pov.Child(quote, decimal.Zero)
+	assert.True(t, qty.Equal(decimal.New(100, 0)))
+	assert.True(t, px.Equal(decimal.New(100, 0)))
+	qty, _ = pov.Child(quote, decimal.New(95, 0))
+	assert.True(t, qty.IsZero())
+
+	pov.OnTrade(&Trade{Symbol: "A", LastQty: decimal.New(50, 0), TradeVolume: decimal.New(600, 0)})
+	qty, _ = pov.Child(quote, decimal.Zero)
+	assert.True(t, qty.Equal(decimal.New(100, 0)))
+
+	//
+	// Within the band the child joins the near price.
+	//
+	fill := &Report{OrderID: "C1", Symbol: "A", ExecID: "E1", ExecType: ExecTypeTrade, LastQty: decimal.New(150, 0), LastPx: decimal.New(100, 0)}
+	pov.OnReport(fill)
+	pov.OnReport(fill)
+	pov.OnReport(&Report{OrderID: "C1", Symbol: "A", ExecID: "E9", ExecType: ExecTypeTradeCancel, LastQty: decimal.New(150, 0)})
+	assert.True(t, pov.Filled().Equal(decimal.New(150, 0)))
+	assert.True(t, pov.Rate().Equal(decimal.RequireFromString("0.15")))
+	qty, px = pov.Child(quote, decimal.Zero)
+	assert.True(t, qty.Equal(decimal.New(60, 0)))
+	assert.True(t, px.Equal(decimal.New(99, 0)))
+
+	//
+	// The price is never outside the limit.
+	//
+	away := &Quote{Symbol: "A", BidPx: decimal.New(102, 0), AskPx: decimal.New(103, 0)}
+	_, px = pov.Child(away, decimal.Zero)
+	assert.True(t, px.Equal(decimal.New(101, 0)))
+
+	//
+	// Above the band no child is needed and the working quantity is excess.
+	//
+	assert.True(t, pov.Excess(decimal.New(40, 0)).IsZero())
+	pov.OnReport(&Report{OrderID: "C1", Symbol: "A", ExecID: "E2", LastQty: decimal.New(200, 0), LastPx: decimal.New(100, 0)})
+	qty, _ = pov.Child(quote, decimal.Zero)
+	assert.True(t, qty.IsZero())
+	assert.True(t, pov.Excess(decimal.New(40, 0)).Equal(decimal.New(40, 0)))
+
+}
+
+func TestParticipationTotal(t *testing.T) {
+
+	listing := &Listing{Symbol: "A", RoundLot: decimal.New(10, 0)}
+	pov, err := NewParticipation(listing, Sell, decimal.RequireFromString("0.5"), WithParticipationQty[*Listing](decimal.New(125, 0)))
+	assert.Nil(t, err)
+
+	pov.OnTrade(&Trade{Symbol: "A", LastQty: decimal.New(1000, 0)})
+	quote := &Quote{Symbol: "A", BidPx: decimal.New(99, 0), AskPx: decimal.New(100, 0)}
+	qty, px := pov.Child(quote, decimal.New(50, 0))
+	assert.True(t, qty.Equal(decimal.New(70, 0)))
+	assert.True(t, px.Equal(decimal.New(99, 0)))
+
+	//
+	// Without a price or a limit there is no child.
+	//
+	qty, _ = pov.Child(&Quote{Symbol: "A"}, decimal.Zero)
+	assert.True(t, qty.IsZero())
+
+}
+
+func TestParticipationBusts(t *testing.T) {
+
+	listing := &Listing{Symbol: "A"}
+	pov, err := NewParticipation(listing, Buy, decimal.RequireFromString("0.1"))
+	assert.Nil(t, err)
+
+	report := func(execID string, execType ExecType, execRefID string, qty int64) *Report {
+		return &Report{Symbol: "A", ExecID: execID, ExecType: execType, ExecRefID: execRefID, LastQty: decimal.New(qty, 0)}
+	}
+
+	pov.OnReport(report("E1", ExecTypeTrade, "", 100))
+	pov.OnReport(report("E2", ExecTypeTrade, "", 50))
+	assert.True(t, pov.Filled().Equal(decimal.New(150, 0)))
+
+	//
+	// A bust takes the fill out, once only.
+	//
+	bust := report("E3", ExecTypeTradeCancel, "E1", 100)
+	pov.OnReport(bust)
+	pov.OnReport(bust)
+	pov.OnReport(report("E4", ExecTypeTradeCancel, "E1", 100))
+	assert.True(t, pov.Filled().Equal(decimal.New(50, 0)))
+
+	//
+	// A correction replaces the quantity of the fill, and may be busted.
+	//
+	pov.OnReport(report("E5", ExecTypeTradeCorrect, "E2", 30))
+	assert.True(t, pov.Filled().Equal(decimal.New(30, 0)))
+	pov.OnReport(report("E6", ExecTypeTradeCorrect, "E2", 40))
+	assert.True(t, pov.Filled().Equal(decimal.New(40, 0)))
+	pov.OnReport(report("E7", ExecTypeTradeCancel, "E2", 0))
+	assert.True(t, pov.Filled().IsZero())
+
+	//
+	// A bust or correction that arrives before its fill is held for it.
+	//
+	pov.OnReport(report("E8", ExecTypeTradeCorrect, "E9", 10))
+	pov.OnReport(report("E10", ExecTypeTradeCancel, "E11", 0))
+	assert.True(t, pov.Filled().IsZero())
+	pov.OnReport(report("E9", ExecTypeTrade, "", 60))
+	assert.True(t, pov.Filled().Equal(decimal.New(10, 0)))
+	pov.OnReport(report("E11", ExecTypeTrade, "", 70))
+	assert.True(t, pov.Filled().Equal(decimal.New(10, 0)))
+
+}
@@ -0,0 +1,24 @@
+package mkt
+
+import "github.com/gbkr-com/utl"
+
+// NewQuotePool returns a [*utl.Pool] of n zeroed quotes.
+func NewQuotePool(n int) *utl.Pool[*Quote] {
+	return utl.NewPool(n, ZeroQuote)
+}
+
+// NewTradePool returns a [*utl.Pool] of n zeroed trades.
+func NewTradePool(n int) *utl.Pool[*Trade] {
+	return utl.NewPool(n, ZeroTrade)
+}
+
+// NewPositionMemoPool returns a [*utl.Pool] of n zeroed memos, for use with
+// [WithPositionPool] and [WithBookPool].
+func NewPositionMemoPool(n int) *utl.Pool[*PositionMemo] {
+	return utl.NewPool(n, ZeroPositionMemo)
+}
+
+// NewReportPool returns a [*utl.Pool] of n zeroed reports.
+func NewReportPool(n int) *utl.Pool[*Report] {
+	return utl.NewPool(n, ZeroReport)
+}
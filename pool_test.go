@@ -0,0 +1,135 @@
+package mkt
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZeroFunctions(t *testing.T) {
+
+	assert.NotNil(t, ZeroTrade(nil))
+	trade := ZeroTrade(&Trade{Symbol: "A", LastQty: DecimalOne, LastPx: DecimalOne, TradeVolume: DecimalOne, AvgPx: DecimalOne})
+	assert.Equal(t, "", trade.Symbol)
+	assert.True(t, trade.LastQty.IsZero() && trade.LastPx.IsZero() && trade.TradeVolume.IsZero() && trade.AvgPx.IsZero())
+
+	assert.NotNil(t, ZeroReport(nil))
+	report := ZeroReport(&Report{OrderID: "A", OrdStatus: OrdStatusFilled, LastQty: DecimalOne})
+	assert.Equal(t, "", report.OrderID)
+	assert.Equal(t, OrdStatus(0), report.OrdStatus)
+	assert.True(t, report.LastQty.IsZero())
+
+	assert.NotNil(t, ZeroPositionMemo(nil))
+	memo := ZeroPositionMemo(&PositionMemo{Symbol: "A", Quantity: DecimalOne, AvgPx: DecimalOne, Realised: DecimalOne})
+	assert.Equal(t, "", memo.Symbol)
+	assert.True(t, memo.Quantity.IsZero() && memo.AvgPx.IsZero() && memo.Realised.IsZero())
+
+}
+
+func TestPositionPool(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A", TickIncrement: decimal.New(1, -2), ContractMultiplier: DecimalOne})
+
+	pool := NewPositionMemoPool(1)
+	memos := make(chan *PositionMemo, 1)
+	book := NewBook("POOL", whitelist, WithBookChannel[*Listing](memos), WithBookPool[*Listing](pool))
+
+	assert.Nil(t, book.Traded("A", Buy, decimal.New(10, 0), decimal.New(42, 0)))
+	first := <-memos
+	assert.True(t, first.Quantity.Equal(decimal.New(10, 0)))
+	pool.Recycle(first)
+
+	assert.Nil(t, book.Traded("A", Buy, decimal.New(10, 0), decimal.New(42, 0)))
+	second := <-memos
+	assert.Same(t, first, second, "reused from the pool")
+	assert.True(t, second.Quantity.Equal(decimal.New(20, 0)))
+
+}
+
+func TestPositionAllocations(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A", TickIncrement: decimal.New(1, -2), ContractMultiplier: DecimalOne})
+
+	pool := NewPositionMemoPool(1)
+	memos := make(chan *PositionMemo, 1)
+	book := NewBook("POOL", whitelist, WithBookChannel[*Listing](memos), WithBookPool[*Listing](pool))
+	qty, px := decimal.New(100, 0), decimal.New(4216, -2)
+
+	//
+	// Only the decimal arithmetic allocates: no history is kept for trades
+	// without a trade ID.
+	//
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = book.Traded("A", Buy, qty, px)
+		pool.Recycle(<-memos)
+		_ = book.Traded("A", Sell, qty, px)
+		pool.Recycle(<-memos)
+	})
+	assert.LessOrEqual(t, allocs, 9.0)
+	book.ForEachPosition(func(position *Position[*Listing]) {
+		assert.Equal(t, 0, len(position.history))
+	})
+
+}
+
+// BenchmarkPipeline measures the steady state allocations of a quote, to a
+// trade at the ask, to a position, to a memo, with and without pools. The
+// decimal arithmetic still allocates; the pools remove the data objects.
+func BenchmarkPipeline(b *testing.B) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A", TickIncrement: decimal.New(1, -2), ContractMultiplier: DecimalOne})
+
+	run := func(b *testing.B, pooled bool) {
+
+		quotes, trades, memoPool := NewQuotePool(1), NewTradePool(1), NewPositionMemoPool(1)
+		quoteChannel, tradeChannel, memos := make(chan *Quote, 1), make(chan *Trade, 1), make(chan *PositionMemo, 1)
+		options := []BookOption[*Listing]{WithBookChannel[*Listing](memos)}
+		if pooled {
+			options = append(options, WithBookPool[*Listing](memoPool))
+		}
+		book := NewBook("BENCH", whitelist, options...)
+		bid, ask, size := decimal.New(4215, -2), decimal.New(4216, -2), decimal.New(100, 0)
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+
+			var quote *Quote
+			var trade *Trade
+			if pooled {
+				quote, trade = quotes.Get(), trades.Get()
+			} else {
+				quote, trade = &Quote{}, &Trade{}
+			}
+			quote.Symbol, quote.BidPx, quote.BidSize, quote.AskPx, quote.AskSize = "A", bid, size, ask, size
+			quoteChannel <- quote
+			quote = <-quoteChannel
+
+			trade.Symbol, trade.LastQty, trade.LastPx = quote.Symbol, quote.AskSize, quote.AskPx
+			tradeChannel <- trade
+			trade = <-tradeChannel
+
+			side := Buy
+			if i%2 == 1 {
+				side = Sell
+			}
+			_ = book.Traded(trade.Symbol, side, trade.LastQty, trade.LastPx)
+			memo := <-memos
+
+			if pooled {
+				quotes.Recycle(quote)
+				trades.Recycle(trade)
+				memoPool.Recycle(memo)
+			}
+
+		}
+
+	}
+
+	b.Run("unpooled", func(b *testing.B) { run(b, false) })
+	b.Run("pooled", func(b *testing.B) { run(b, true) })
+
+}
@@ -1,15 +1,45 @@
 package mkt
 
-import "github.com/shopspring/decimal"
+import (
+	"fmt"
 
-// A Position as a result of one or more trades in a [Listing].
+	"github.com/gbkr-com/utl"
+	"github.com/shopspring/decimal"
+)
+
+// A Position as a result of one or more trades in a [Listing]. While the
+// position holds a trade with a trade ID, from [Position.TradedWithID], it
+// keeps its history so that the trade can be cancelled or corrected. Otherwise
+// no history is kept.
 type Position[T AnyListing] struct {
 	symbol    string
 	whitelist *WhiteList[T]
+	listing   T                  // As last found in the white list.
+	listed    bool               // The listing has been found.
 	quantity  decimal.Decimal    // Long is positive, short is negative.
 	avgPx     decimal.Decimal    // Average price of building the position.
 	realised  decimal.Decimal    // Realised profit/loss.
 	c         chan *PositionMemo // Optional channel.
+	pool      *utl.Pool[*PositionMemo]
+	base      PositionMemo     // The position before the history.
+	history   []positionEvent  // Everything that changed the position, oldest first.
+	trades    map[string]*Fill // By TradeID.
+}
+
+// Fill is one trade in the history of a [Position]. Only a fill with a
+// TradeID can be cancelled or corrected.
+type Fill struct {
+	TradeID string          `json:"tradeID,omitempty"`
+	Side    Side            `json:"side"`
+	LastQty decimal.Decimal `json:"lastQty"`
+	LastPx  decimal.Decimal `json:"lastPx"`
+}
+
+// positionEvent is a fill, a cash movement or a reset.
+type positionEvent struct {
+	fill  *Fill
+	cash  decimal.Decimal
+	reset bool
 }
 
 // PositionMemo is the key information for each position.
@@ -30,9 +60,19 @@ func WithPositionChannel[T AnyListing](c chan *PositionMemo) PositionOption[T] {
 	}
 }
 
+// WithPositionPool takes each [*PositionMemo] written to the channel from the
+// pool, rather than allocating it. The receiver owns each memo and should
+// recycle it to the pool when done.
+func WithPositionPool[T AnyListing](pool *utl.Pool[*PositionMemo]) PositionOption[T] {
+	return func(x *Position[T]) {
+		x.pool = pool
+	}
+}
+
 // NewPosition returns a flat position for the given symbol.
 func NewPosition[T AnyListing](symbol string, whitelist *WhiteList[T], options ...PositionOption[T]) *Position[T] {
 	position := &Position[T]{symbol: symbol, whitelist: whitelist}
+	position.definition()
 	for _, option := range options {
 		option(position)
 	}
@@ -41,12 +81,27 @@ func NewPosition[T AnyListing](symbol string, whitelist *WhiteList[T], options .
 
 // Memo returns a [*PositionMemo] for the current position.
 func (x *Position[T]) Memo() *PositionMemo {
-	return &PositionMemo{
-		Symbol:   x.symbol,
-		Quantity: x.quantity,
-		AvgPx:    x.avgPx,
-		Realised: x.realised,
+	return x.MemoInto(&PositionMemo{})
+}
+
+// MemoInto writes the current position into the memo and returns it.
+func (x *Position[T]) MemoInto(memo *PositionMemo) *PositionMemo {
+	memo.Symbol = x.symbol
+	memo.Quantity = x.quantity
+	memo.AvgPx = x.avgPx
+	memo.Realised = x.realised
+	return memo
+}
+
+// ZeroPositionMemo will 'zero' all the fields in the [*PositionMemo], never
+// returning nil. This is a convenience for using memos in a [utl.Pool].
+func ZeroPositionMemo(memo *PositionMemo) *PositionMemo {
+	if memo == nil {
+		return &PositionMemo{}
 	}
+	memo.Symbol = ""
+	memo.Quantity, memo.AvgPx, memo.Realised = decimal.Zero, decimal.Zero, decimal.Zero
+	return memo
 }
 
 // Traded adjusts this position for the given trade.
@@ -56,12 +111,143 @@ func (x *Position[T]) Traded(side Side, lastQty decimal.Decimal, lastPx decimal.
 		return
 	}
 
-	defer func() {
-		if x.c == nil {
-			return
+	if x.recording() {
+		x.history = append(x.history, positionEvent{fill: &Fill{Side: side, LastQty: lastQty, LastPx: lastPx}})
+	}
+	x.trade(side, lastQty, lastPx)
+	x.publish()
+
+}
+
+// TradedWithID adjusts this position for the given trade, as [Position.Traded],
+// keeping the trade ID so that the trade can later be cancelled or corrected.
+// It returns an error if the trade ID is empty or has been used before.
+func (x *Position[T]) TradedWithID(tradeID string, side Side, lastQty decimal.Decimal, lastPx decimal.Decimal) error {
+
+	if tradeID == "" {
+		return fmt.Errorf("mkt.Position: %s trade without an ID", x.symbol)
+	}
+	if _, ok := x.trades[tradeID]; ok {
+		return fmt.Errorf("mkt.Position: %s trade %s already exists", x.symbol, tradeID)
+	}
+	//
+	// Even a trade for no quantity is kept, since it may be corrected.
+	//
+	fill := &Fill{TradeID: tradeID, Side: side, LastQty: lastQty, LastPx: lastPx}
+	if !x.recording() {
+		x.MemoInto(&x.base)
+		x.history = x.history[:0]
+	}
+	if x.trades == nil {
+		x.trades = map[string]*Fill{}
+	}
+	x.trades[tradeID] = fill
+	x.history = append(x.history, positionEvent{fill: fill})
+	if lastQty.IsZero() {
+		return nil
+	}
+	x.trade(side, lastQty, lastPx)
+	x.publish()
+	return nil
+
+}
+
+// Cancel recomputes the position as if the trade had never happened, for
+// example when the venue busts the trade. It returns an error if there is no
+// such trade.
+func (x *Position[T]) Cancel(tradeID string) error {
+
+	fill, ok := x.trades[tradeID]
+	if !ok {
+		return fmt.Errorf("mkt.Position: %s has no trade %s", x.symbol, tradeID)
+	}
+	delete(x.trades, tradeID)
+	for i, event := range x.history {
+		if event.fill == fill {
+			x.history = append(x.history[:i], x.history[i+1:]...)
+			break
+		}
+	}
+	x.replay()
+	if !x.recording() {
+		x.Compact()
+	}
+	x.publish()
+	return nil
+
+}
+
+// Correct recomputes the position as if the trade had happened at the
+// corrected quantity and price. It returns an error if there is no such
+// trade.
+func (x *Position[T]) Correct(tradeID string, lastQty decimal.Decimal, lastPx decimal.Decimal) error {
+
+	fill, ok := x.trades[tradeID]
+	if !ok {
+		return fmt.Errorf("mkt.Position: %s has no trade %s", x.symbol, tradeID)
+	}
+	fill.LastQty, fill.LastPx = lastQty, lastPx
+	x.replay()
+	x.publish()
+	return nil
+
+}
+
+// Compact forgets the history, so that no trade before now can be cancelled
+// or corrected, for example once the trades have settled.
+func (x *Position[T]) Compact() {
+	x.history, x.trades = nil, nil
+}
+
+// Fills returns the trades in the history, oldest first.
+func (x *Position[T]) Fills() []Fill {
+	var fills []Fill
+	for _, event := range x.history {
+		if event.fill != nil {
+			fills = append(fills, *event.fill)
+		}
+	}
+	return fills
+}
+
+// recording returns true while the history is kept.
+func (x *Position[T]) recording() bool {
+	return len(x.trades) > 0
+}
+
+// replay recomputes the position from its history. Realised profit/loss
+// before the most recent [Position.Reset] is not restated.
+func (x *Position[T]) replay() {
+	x.quantity, x.avgPx, x.realised = x.base.Quantity, x.base.AvgPx, x.base.Realised
+	for _, event := range x.history {
+		switch {
+		case event.fill != nil:
+			if !event.fill.LastQty.IsZero() {
+				x.trade(event.fill.Side, event.fill.LastQty, event.fill.LastPx)
+			}
+		case event.reset:
+			x.realised = decimal.Zero
+		default:
+			x.realised = x.realised.Add(event.cash)
 		}
-		x.c <- x.Memo()
-	}()
+	}
+}
+
+// publish writes a memo to the channel, if there is one.
+func (x *Position[T]) publish() {
+	if x.c == nil {
+		return
+	}
+	if x.pool != nil {
+		x.c <- x.MemoInto(x.pool.Get())
+		return
+	}
+	x.c <- x.Memo()
+}
+
+// trade applies the trade to the quantity, average price and realised
+// profit/loss.
+func (x *Position[T]) trade(side Side, lastQty decimal.Decimal, lastPx decimal.Decimal) {
 
 	//
 	// Adjust the sign for sales.
@@ -120,12 +306,18 @@ func (x *Position[T]) Traded(side Side, lastQty decimal.Decimal, lastPx decimal.
 // Cash adds cash to the realised profit/loss, representing a cash only movement
 // such as a dividend. The cash may be negative.
 func (x *Position[T]) Cash(cash decimal.Decimal) {
+	if x.recording() {
+		x.history = append(x.history, positionEvent{cash: cash})
+	}
 	x.realised = x.realised.Add(cash)
 }
 
 // Reset the realised profit/loss. Typically this would be done at the end of
 // an accounting period.
 func (x *Position[T]) Reset() {
+	if x.recording() {
+		x.history = append(x.history, positionEvent{reset: true})
+	}
 	x.realised = decimal.Zero
 }
 
@@ -142,14 +334,37 @@ func (x *Position[T]) Mark(price decimal.Decimal) (valuation, unrealised decimal
 }
 
 func (x *Position[T]) fromListing() (precision int32, contractMultiplier decimal.Decimal) {
+	def := x.definition()
+	if def == nil {
+		return fromListing(x.whitelist, x.symbol)
+	}
+	return def.PricePrecision() + 1, def.ContractMultiplier
+}
+
+// definition returns the listing from the white list or, once the symbol has
+// been removed, such as after expiry, as it was last found. It returns nil if
+// the symbol has never been whitelisted.
+func (x *Position[T]) definition() *Listing {
+	if listing, ok := x.whitelist.Lookup(x.symbol); ok {
+		x.listing, x.listed = listing, true
+	}
+	if !x.listed {
+		return nil
+	}
+	return x.listing.Definition()
+}
+
+// fromListing returns the precision for average prices and the contract
+// multiplier of the symbol, with defaults if it is not whitelisted.
+func fromListing[T AnyListing](whitelist *WhiteList[T], symbol string) (precision int32, contractMultiplier decimal.Decimal) {
 
 	precision = 8
 	contractMultiplier = DecimalOne
 
-	listing, ok := x.whitelist.Lookup(x.symbol)
+	listing, ok := whitelist.Lookup(symbol)
 	if ok {
 		def := listing.Definition()
-		precision = Precision(def.TickIncrement) + 1
+		precision = def.PricePrecision() + 1
 		contractMultiplier = def.ContractMultiplier
 	}
 
@@ -282,3 +282,112 @@ func TestPositionChannel(t *testing.T) {
 	assert.True(t, m.AvgPx.Equal(decimal42))
 
 }
+
+func TestPositionCancelCorrect(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A", ContractMultiplier: DecimalOne})
+	memos := make(chan *PositionMemo, 16)
+	book := NewBook("B", whitelist, WithBookChannel[*Listing](memos))
+
+	assert.Nil(t, book.TradedWithID("A", "T1", Buy, decimal.New(10, 0), decimal.New(100, 0)))
+	assert.Nil(t, book.TradedWithID("A", "T2", Sell, decimal.New(5, 0), decimal.New(110, 0)))
+	assert.NotNil(t, book.TradedWithID("A", "T2", Sell, decimal.New(5, 0), decimal.New(110, 0)))
+	assert.NotNil(t, book.TradedWithID("A", "", Sell, decimal.New(5, 0), decimal.New(110, 0)))
+	assert.NotNil(t, book.TradedWithID("Z", "T3", Sell, decimal.New(5, 0), decimal.New(110, 0)))
+	assert.Nil(t, book.Traded("A", Buy, decimal.New(1, 0), decimal.New(100, 0)))
+
+	var position *Position[*Listing]
+	book.ForEachPosition(func(p *Position[*Listing]) { position = p })
+	position.Cash(decimal.New(7, 0))
+
+	memo := position.Memo()
+	assert.True(t, memo.Quantity.Equal(decimal.New(6, 0)))
+	assert.True(t, memo.Realised.Equal(decimal.New(57, 0)))
+	assert.Equal(t, 3, len(position.Fills()))
+	assert.Equal(t, "T1", position.Fills()[0].TradeID)
+	for len(memos) > 0 {
+		<-memos
+	}
+
+	//
+	// Without the first buy the sale opens a short position, so there is no
+	// realised profit except the cash.
+	//
+	assert.Nil(t, book.Cancel("A", "T1"))
+	memo = <-memos
+	assert.True(t, memo.Quantity.Equal(decimal.New(-4, 0)))
+	assert.True(t, memo.AvgPx.Equal(decimal.New(110, 0)))
+	assert.True(t, memo.Realised.Equal(decimal.New(17, 0)))
+	assert.Equal(t, 2, len(position.Fills()))
+	assert.NotNil(t, book.Cancel("A", "T1"))
+
+	assert.Nil(t, book.Correct("A", "T2", decimal.New(4, 0), decimal.New(120, 0)))
+	memo = <-memos
+	assert.True(t, memo.Quantity.Equal(decimal.New(-3, 0)))
+	assert.True(t, memo.AvgPx.Equal(decimal.New(120, 0)))
+	assert.True(t, memo.Realised.Equal(decimal.New(27, 0)))
+
+	//
+	// Realised profit/loss before a reset is not restated.
+	//
+	position.Reset()
+	assert.Nil(t, book.Correct("A", "T2", decimal.New(4, 0), decimal.New(90, 0)))
+	memo = <-memos
+	assert.True(t, memo.Realised.IsZero())
+
+	assert.NotNil(t, book.Correct("A", "T9", decimal.New(1, 0), decimal.New(1, 0)))
+	assert.NotNil(t, book.Cancel("B", "T2"))
+
+	//
+	// Once compacted the trade is final.
+	//
+	book.Compact()
+	assert.Equal(t, 0, len(position.Fills()))
+	assert.NotNil(t, book.Correct("A", "T2", decimal.New(4, 0), decimal.New(90, 0)))
+	memo = position.Memo()
+	assert.True(t, memo.Quantity.Equal(decimal.New(-3, 0)))
+	assert.True(t, memo.AvgPx.Equal(decimal.New(90, 0)))
+	assert.NotNil(t, book.Correct("B", "T2", decimal.New(1, 0), decimal.New(1, 0)))
+
+}
+
+func TestPositionHistory(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A", ContractMultiplier: DecimalOne})
+	position := NewPosition("A", whitelist)
+
+	//
+	// No history is kept until there is a trade with an ID.
+	//
+	position.Traded(Buy, decimal.New(10, 0), decimal.New(100, 0))
+	position.Cash(decimal.New(3, 0))
+	position.Reset()
+	position.Traded(Sell, decimal.New(4, 0), decimal.New(110, 0))
+	assert.Equal(t, 0, len(position.history))
+
+	//
+	// The trades before the first trade with an ID are not restated.
+	//
+	assert.Nil(t, position.TradedWithID("T1", Buy, decimal.New(6, 0), decimal.New(120, 0)))
+	position.Traded(Sell, decimal.New(2, 0), decimal.New(130, 0))
+	assert.Equal(t, 2, len(position.Fills()))
+	assert.Nil(t, position.Correct("T1", decimal.New(2, 0), decimal.New(120, 0)))
+	memo := position.Memo()
+	assert.True(t, memo.Quantity.Equal(decimal.New(6, 0)))
+	assert.True(t, memo.AvgPx.Equal(decimal.New(105, 0)))
+	assert.True(t, memo.Realised.Equal(decimal.New(90, 0)))
+
+	//
+	// Cancelling the only trade with an ID ends the history.
+	//
+	assert.Nil(t, position.Cancel("T1"))
+	memo = position.Memo()
+	assert.True(t, memo.Quantity.Equal(decimal.New(4, 0)))
+	assert.True(t, memo.Realised.Equal(decimal.New(100, 0)))
+	assert.Equal(t, 0, len(position.history))
+	position.Traded(Buy, decimal.New(1, 0), decimal.New(100, 0))
+	assert.Equal(t, 0, len(position.history))
+
+}
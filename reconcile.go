@@ -0,0 +1,382 @@
+package mkt
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// BreakType classifies a [Break].
+type BreakType string
+
+// Recognised BreakType values.
+const (
+	BreakMissingInternal     BreakType = "MISSING_INTERNAL"     // A counterparty fill that we do not have.
+	BreakMissingCounterparty BreakType = "MISSING_COUNTERPARTY" // A fill of ours that the counterparty does not have.
+	BreakDuplicate           BreakType = "DUPLICATE"            // A fill repeated in the same stream.
+	BreakQuantity            BreakType = "QUANTITY"             // Matched fills with a different LastQty.
+	BreakPrice               BreakType = "PRICE"                // Matched fills with a different LastPx.
+	BreakPosition            BreakType = "POSITION"             // The book does not agree with the counterparty fills.
+	BreakOrphan              BreakType = "ORPHAN"               // A bust or correction of a fill not in the same stream.
+)
+
+// Break is one difference found by a [Reconciler]. Fill breaks carry the
+// fills concerned, while a position break carries the two quantities.
+type Break struct {
+	Type            BreakType       `json:"type"`
+	Symbol          string          `json:"symbol"`
+	OrderID         string          `json:"orderID,omitempty"`
+	Internal        *Report         `json:"internal,omitempty"`
+	Counterparty    *Report         `json:"counterparty,omitempty"`
+	BookQty         decimal.Decimal `json:"bookQty"`         // Position breaks only.
+	CounterpartyQty decimal.Decimal `json:"counterpartyQty"` // Position breaks only.
+}
+
+// BreakReport is the result of [Reconciler.Reconcile], intended to be
+// marshalled as JSON.
+type BreakReport struct {
+	Book    string   `json:"book"`
+	Matched int      `json:"matched"` // Fills that agree.
+	Breaks  []*Break `json:"breaks"`
+}
+
+// Clean returns true if there are no breaks.
+func (x *BreakReport) Clean() bool {
+	return len(x.Breaks) == 0
+}
+
+// Reconciler compares a [Book], and the [Report] stream that built it, with
+// the fills reported by the counterparty, such as from a drop copy or an end
+// of day file.
+//
+// Fills are reports with a positive LastQty and an ExecType of trade, or none.
+// A bust, ExecType H, removes the fill named by its ExecRefID and a
+// correction, ExecType G, replaces the LastQty and LastPx of that fill, before
+// the fills are compared. A counterparty fill belongs to
+// one of our orders if its OrderID, SecondaryOrderID or ClOrdID matches ours,
+// tried in that order. Within an order, fills with the same quantity and
+// price are matched first, then the rest are paired in sequence and any
+// difference reported. A report with the same ExecID as an earlier report in
+// the same stream is a duplicate or, if it has no ExecID, one with the same
+// order, quantity, price and TransactTime.
+type Reconciler[T AnyListing] struct {
+	book      *Book[T]
+	opening   map[string]decimal.Decimal
+	tolerance decimal.Decimal
+	internal  []*Report
+}
+
+// ReconcilerOption is any option that can be applied when constructing the
+// reconciler.
+type ReconcilerOption[T AnyListing] func(*Reconciler[T])
+
+// WithOpeningPositions sets the position by symbol before the first fill, so
+// that the counterparty fills can be compared with the book.
+func WithOpeningPositions[T AnyListing](positions map[string]decimal.Decimal) ReconcilerOption[T] {
+	return func(x *Reconciler[T]) {
+		x.opening = positions
+	}
+}
+
+// WithPriceTolerance allows matched fill prices to differ by up to the
+// tolerance, for example where the counterparty rounds prices.
+func WithPriceTolerance[T AnyListing](tolerance decimal.Decimal) ReconcilerOption[T] {
+	return func(x *Reconciler[T]) {
+		x.tolerance = tolerance
+	}
+}
+
+// NewReconciler returns a [*Reconciler] for the book.
+func NewReconciler[T AnyListing](book *Book[T], options ...ReconcilerOption[T]) *Reconciler[T] {
+	reconciler := &Reconciler[T]{book: book}
+	for _, option := range options {
+		option(reconciler)
+	}
+	return reconciler
+}
+
+// Internal records one of our reports, if it is a fill, bust or correction.
+// The report is copied, so may be recycled once this function returns.
+func (x *Reconciler[T]) Internal(report *Report) {
+	if !reconcilable(report) {
+		return
+	}
+	copied := *report
+	x.internal = append(x.internal, &copied)
+}
+
+// Reconcile compares the recorded reports and the book with the counterparty
+// fills. Breaks are ordered by symbol, then OrderID.
+func (x *Reconciler[T]) Reconcile(counterparty []*Report) *BreakReport {
+
+	result := &BreakReport{Book: x.book.Name()}
+
+	ours, breaks := dedupeFills(x.internal, func(r *Report) *Break {
+		return &Break{Type: BreakDuplicate, Symbol: r.Symbol, OrderID: r.OrderID, Internal: r}
+	})
+	result.Breaks = append(result.Breaks, breaks...)
+	ours, breaks = settleFills(ours, func(r *Report) *Break {
+		return &Break{Type: BreakOrphan, Symbol: r.Symbol, OrderID: r.OrderID, Internal: r}
+	})
+	result.Breaks = append(result.Breaks, breaks...)
+
+	var theirs []*Report
+	for _, report := range counterparty {
+		if reconcilable(report) {
+			theirs = append(theirs, report)
+		}
+	}
+	theirs, breaks = dedupeFills(theirs, func(r *Report) *Break {
+		return &Break{Type: BreakDuplicate, Symbol: r.Symbol, OrderID: r.OrderID, Counterparty: r}
+	})
+	result.Breaks = append(result.Breaks, breaks...)
+	theirs, breaks = settleFills(theirs, func(r *Report) *Break {
+		return &Break{Type: BreakOrphan, Symbol: r.Symbol, OrderID: r.OrderID, Counterparty: r}
+	})
+	result.Breaks = append(result.Breaks, breaks...)
+
+	//
+	// Group the fills by our OrderID, resolving each counterparty fill
+	// through whichever identifier matches.
+	//
+	byOrderID, bySecondary, byClOrdID := map[string]string{}, map[string]string{}, map[string]string{}
+	groups := map[string]*fillGroup{}
+	var keys []string
+	group := func(key string) *fillGroup {
+		g := groups[key]
+		if g == nil {
+			g = &fillGroup{}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		return g
+	}
+	for _, r := range ours {
+		byOrderID[r.OrderID] = r.OrderID
+		if r.SecondaryOrderID != "" {
+			bySecondary[r.SecondaryOrderID] = r.OrderID
+		}
+		if r.ClOrdID != "" {
+			byClOrdID[r.ClOrdID] = r.OrderID
+		}
+		g := group(r.OrderID)
+		g.ours = append(g.ours, r)
+	}
+	for _, r := range theirs {
+		key, ok := byOrderID[r.OrderID]
+		if !ok && r.SecondaryOrderID != "" {
+			key, ok = bySecondary[r.SecondaryOrderID]
+		}
+		if !ok && r.ClOrdID != "" {
+			key, ok = byClOrdID[r.ClOrdID]
+		}
+		if !ok {
+			key = "\x00" + r.OrderID // Cannot collide with one of ours.
+		}
+		g := group(key)
+		g.theirs = append(g.theirs, r)
+	}
+
+	for _, key := range keys {
+		matched, breaks := x.matchFills(groups[key])
+		result.Matched += matched
+		result.Breaks = append(result.Breaks, breaks...)
+	}
+
+	result.Breaks = append(result.Breaks, x.positionBreaks(theirs)...)
+
+	sort.SliceStable(result.Breaks, func(i, j int) bool {
+		a, b := result.Breaks[i], result.Breaks[j]
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.OrderID < b.OrderID
+	})
+	return result
+
+}
+
+type fillGroup struct {
+	ours   []*Report
+	theirs []*Report
+}
+
+// matchFills matches the fills of one order.
+func (x *Reconciler[T]) matchFills(g *fillGroup) (int, []*Break) {
+
+	matched := 0
+	var breaks []*Break
+
+	//
+	// First the exact matches.
+	//
+	ours := append([]*Report(nil), g.ours...)
+	var theirs []*Report
+	for _, their := range g.theirs {
+		found := false
+		for i, our := range ours {
+			if our.LastQty.Equal(their.LastQty) && x.samePrice(our.LastPx, their.LastPx) {
+				ours = append(ours[:i], ours[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if found {
+			matched++
+		} else {
+			theirs = append(theirs, their)
+		}
+	}
+
+	//
+	// Then pair the rest in sequence.
+	//
+	for len(ours) > 0 && len(theirs) > 0 {
+		our, their := ours[0], theirs[0]
+		ours, theirs = ours[1:], theirs[1:]
+		if !our.LastQty.Equal(their.LastQty) {
+			breaks = append(breaks, &Break{Type: BreakQuantity, Symbol: our.Symbol, OrderID: our.OrderID, Internal: our, Counterparty: their})
+		}
+		if !x.samePrice(our.LastPx, their.LastPx) {
+			breaks = append(breaks, &Break{Type: BreakPrice, Symbol: our.Symbol, OrderID: our.OrderID, Internal: our, Counterparty: their})
+		}
+	}
+	for _, our := range ours {
+		breaks = append(breaks, &Break{Type: BreakMissingCounterparty, Symbol: our.Symbol, OrderID: our.OrderID, Internal: our})
+	}
+	for _, their := range theirs {
+		breaks = append(breaks, &Break{Type: BreakMissingInternal, Symbol: their.Symbol, OrderID: their.OrderID, Counterparty: their})
+	}
+
+	return matched, breaks
+
+}
+
+func (x *Reconciler[T]) samePrice(a, b decimal.Decimal) bool {
+	return a.Sub(b).Abs().LessThanOrEqual(x.tolerance)
+}
+
+// positionBreaks compares the book with the opening positions plus the
+// counterparty fills, for every symbol in any of them.
+func (x *Reconciler[T]) positionBreaks(theirs []*Report) []*Break {
+
+	expected := map[string]decimal.Decimal{}
+	for symbol, quantity := range x.opening {
+		expected[symbol] = quantity
+	}
+	for _, r := range theirs {
+		switch r.Side {
+		case Buy:
+			expected[r.Symbol] = expected[r.Symbol].Add(r.LastQty)
+		case Sell:
+			expected[r.Symbol] = expected[r.Symbol].Sub(r.LastQty)
+		}
+	}
+
+	actual := map[string]decimal.Decimal{}
+	x.book.ForEachPosition(func(position *Position[T]) {
+		actual[position.symbol] = position.quantity
+		if _, ok := expected[position.symbol]; !ok {
+			expected[position.symbol] = decimal.Zero
+		}
+	})
+
+	var breaks []*Break
+	for symbol, quantity := range expected {
+		if !quantity.Equal(actual[symbol]) {
+			breaks = append(breaks, &Break{Type: BreakPosition, Symbol: symbol, BookQty: actual[symbol], CounterpartyQty: quantity})
+		}
+	}
+	return breaks
+
+}
+
+// reconcilable returns true if the report is a fill, bust or correction.
+func reconcilable(report *Report) bool {
+	switch report.ExecType {
+	case 0, ExecTypeTrade:
+		return report.LastQty.IsPositive()
+	case ExecTypeTradeCancel, ExecTypeTradeCorrect:
+		return true
+	default:
+		return false
+	}
+}
+
+// dedupeFills returns the reports without duplicates, and a break for each
+// duplicate.
+func dedupeFills(fills []*Report, duplicate func(*Report) *Break) ([]*Report, []*Break) {
+
+	type key struct {
+		execID       string
+		orderID      string
+		lastQty      string
+		lastPx       string
+		transactTime int64
+	}
+
+	seen := map[key]bool{}
+	var unique []*Report
+	var breaks []*Break
+	for _, r := range fills {
+		k := key{execID: r.ExecID}
+		if r.ExecID == "" {
+			k = key{"", r.OrderID, r.LastQty.String(), r.LastPx.String(), r.TransactTime.UnixNano()}
+		}
+		if seen[k] {
+			breaks = append(breaks, duplicate(r))
+			continue
+		}
+		seen[k] = true
+		unique = append(unique, r)
+	}
+	return unique, breaks
+
+}
+
+// settleFills applies the busts and corrections in the reports to the fills
+// named by their ExecRefID, returning the fills that stand, in order, and a
+// break for each bust or correction of a fill that is not in the reports. A
+// corrected fill is a copy, so the reports are not changed.
+func settleFills(reports []*Report, orphan func(*Report) *Break) ([]*Report, []*Break) {
+
+	var fills []*Report
+	byExecID := map[string]int{}
+	for _, r := range reports {
+		if r.ExecType == ExecTypeTradeCancel || r.ExecType == ExecTypeTradeCorrect {
+			continue
+		}
+		if r.ExecID != "" {
+			byExecID[r.ExecID] = len(fills)
+		}
+		fills = append(fills, r)
+	}
+
+	var breaks []*Break
+	for _, r := range reports {
+		if r.ExecType != ExecTypeTradeCancel && r.ExecType != ExecTypeTradeCorrect {
+			continue
+		}
+		i, ok := byExecID[r.ExecRefID]
+		if !ok || fills[i] == nil {
+			breaks = append(breaks, orphan(r))
+			continue
+		}
+		if r.ExecType == ExecTypeTradeCancel || !r.LastQty.IsPositive() {
+			fills[i] = nil
+			continue
+		}
+		corrected := *fills[i]
+		corrected.LastQty, corrected.LastPx = r.LastQty, r.LastPx
+		fills[i] = &corrected
+	}
+
+	standing := fills[:0]
+	for _, fill := range fills {
+		if fill != nil {
+			standing = append(standing, fill)
+		}
+	}
+	return standing, breaks
+
+}
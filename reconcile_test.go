@@ -0,0 +1,178 @@
+package mkt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFill(orderID, secondaryOrderID, symbol string, side Side, qty, px int64, seconds int) *Report {
+	return &Report{
+		OrderID:          orderID,
+		SecondaryOrderID: secondaryOrderID,
+		Symbol:           symbol,
+		Side:             side,
+		OrdStatus:        OrdStatusPartiallyFilled,
+		LastQty:          decimal.New(qty, 0),
+		LastPx:           decimal.New(px, 0),
+		TransactTime:     time.Date(2024, 3, 1, 9, 0, seconds, 0, time.UTC),
+	}
+}
+
+func TestReconcileClean(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A"})
+	book := NewBook("B", whitelist)
+	reconciler := NewReconciler(book, WithOpeningPositions[*Listing](map[string]decimal.Decimal{"A": decimal.New(5, 0)}))
+
+	assert.Nil(t, book.Traded("A", Buy, decimal.New(5, 0), decimal.New(10, 0)))
+	assert.Nil(t, book.Traded("A", Buy, decimal.New(5, 0), decimal.New(10, 0)))
+	assert.Nil(t, book.Traded("A", Buy, decimal.New(5, 0), decimal.New(11, 0)))
+	reconciler.Internal(testFill("1", "X1", "A", Buy, 5, 10, 1))
+	reconciler.Internal(testFill("1", "X1", "A", Buy, 5, 11, 2))
+	reconciler.Internal(&Report{OrderID: "1", OrdStatus: OrdStatusNew}) // Not a fill.
+
+	//
+	// The counterparty knows the order by its own ID, and reports the fills
+	// in a different order.
+	//
+	theirs := []*Report{
+		testFill("", "X1", "A", Buy, 5, 11, 2),
+		testFill("", "X1", "A", Buy, 5, 10, 1),
+	}
+	result := reconciler.Reconcile(theirs)
+	assert.True(t, result.Clean())
+	assert.Equal(t, "B", result.Book)
+	assert.Equal(t, 2, result.Matched)
+
+}
+
+func TestReconcileBreaks(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A"})
+	whitelist.Add(&Listing{Symbol: "B"})
+	book := NewBook("B", whitelist)
+	reconciler := NewReconciler(book, WithPriceTolerance[*Listing](decimal.New(1, -2)))
+
+	ours := []*Report{
+		testFill("1", "", "A", Buy, 10, 100, 1),
+		testFill("1", "", "A", Buy, 10, 101, 2),
+		testFill("2", "", "A", Sell, 5, 102, 3),
+		testFill("3", "", "B", Buy, 7, 50, 4),
+		testFill("3", "", "B", Buy, 7, 50, 4), // Duplicate.
+	}
+	ours[3].ClOrdID, ours[4].ClOrdID = "C3", "C3"
+	for _, r := range ours {
+		assert.Nil(t, book.Traded(r.Symbol, r.Side, r.LastQty, r.LastPx))
+		reconciler.Internal(r)
+	}
+
+	theirs := []*Report{
+		testFill("1", "", "A", Buy, 10, 100, 1),
+		testFill("1", "", "A", Buy, 9, 103, 2),                 // Quantity and price.
+		testFill("", "", "B", Buy, 7, 50, 4),                   // Matched by ClOrdID.
+		testFill("9", "", "B", Sell, 1, 50, 5),                 // Not ours.
+		testFill("9", "", "B", Sell, 1, 50, 5),                 // Duplicate.
+		testFill("1", "", "A", Buy, 0, 0, 6),                   // Not a fill.
+		{OrderID: "1", Symbol: "A", LastPx: decimal.New(1, 0)}, // Not a fill.
+	}
+	theirs[0].LastPx = decimal.New(100005, -3) // Within tolerance.
+	theirs[2].ClOrdID = "C3"
+
+	result := reconciler.Reconcile(theirs)
+	assert.False(t, result.Clean())
+	assert.Equal(t, 2, result.Matched)
+
+	var types []string
+	for _, b := range result.Breaks {
+		types = append(types, b.Symbol+" "+b.OrderID+" "+string(b.Type))
+	}
+	assert.ElementsMatch(t, []string{
+		"A 1 QUANTITY",
+		"A 1 PRICE",
+		"A 2 MISSING_COUNTERPARTY",
+		"A  POSITION",
+		"B 3 DUPLICATE",
+		"B 9 MISSING_INTERNAL",
+		"B 9 DUPLICATE",
+		"B  POSITION",
+	}, types)
+	assert.Equal(t, "A", result.Breaks[0].Symbol)
+
+	for _, b := range result.Breaks {
+		switch {
+		case b.Type == BreakPosition && b.Symbol == "A":
+			assert.True(t, b.BookQty.Equal(decimal.New(15, 0)))
+			assert.True(t, b.CounterpartyQty.Equal(decimal.New(19, 0)))
+		case b.Type == BreakPosition && b.Symbol == "B":
+			assert.True(t, b.BookQty.Equal(decimal.New(14, 0)))
+			assert.True(t, b.CounterpartyQty.Equal(decimal.New(6, 0)))
+		case b.Type == BreakPrice:
+			assert.True(t, b.Internal.LastPx.Equal(decimal.New(101, 0)))
+			assert.True(t, b.Counterparty.LastPx.Equal(decimal.New(103, 0)))
+		}
+	}
+
+	//
+	// The report is machine readable.
+	//
+	b, err := json.Marshal(result)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(b), `"type":"MISSING_INTERNAL"`))
+
+}
+
+func TestReconcileBustsAndCorrections(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A"})
+	book := NewBook("B", whitelist)
+	reconciler := NewReconciler(book)
+
+	fill := func(execID string, execType ExecType, execRefID string, qty, px int64, seconds int) *Report {
+		r := testFill("1", "", "A", Buy, qty, px, seconds)
+		r.ExecID, r.ExecType, r.ExecRefID = execID, execType, execRefID
+		return r
+	}
+
+	//
+	// Two fills of the same quantity and price at the same time are distinct
+	// when they have distinct ExecIDs.
+	//
+	assert.Nil(t, book.Traded("A", Buy, decimal.New(10, 0), decimal.New(100, 0)))
+	assert.Nil(t, book.Traded("A", Buy, decimal.New(10, 0), decimal.New(100, 0)))
+	reconciler.Internal(fill("E1", ExecTypeTrade, "", 10, 100, 1))
+	reconciler.Internal(fill("E2", ExecTypeTrade, "", 10, 100, 1))
+	reconciler.Internal(fill("E3", ExecTypeTrade, "", 5, 101, 2))
+	reconciler.Internal(fill("E4", ExecTypeTradeCancel, "E3", 5, 101, 3))
+	reconciler.Internal(fill("E5", ExecTypeNew, "", 10, 100, 4)) // Not a fill.
+
+	theirs := []*Report{
+		fill("X1", ExecTypeTrade, "", 10, 100, 1),
+		fill("X2", ExecTypeTrade, "", 10, 99, 1),
+		fill("X2", ExecTypeTrade, "", 10, 99, 1),           // Duplicate.
+		fill("X3", ExecTypeTrade, "", 5, 101, 2),           // Busted.
+		fill("X4", ExecTypeTradeCorrect, "X2", 10, 100, 3), // Corrected price.
+		fill("X5", ExecTypeTradeCancel, "X3", 0, 0, 4),     //
+		fill("X6", ExecTypeTradeCancel, "X9", 0, 0, 5),     // Unknown fill.
+		fill("X7", ExecTypeCanceled, "", 10, 100, 6),       // Not a fill.
+		testFill("1", "", "A", Buy, 0, 0, 7),               // Not a fill.
+	}
+
+	result := reconciler.Reconcile(theirs)
+	assert.Equal(t, 2, result.Matched)
+
+	var types []string
+	for _, b := range result.Breaks {
+		types = append(types, b.Symbol+" "+b.OrderID+" "+string(b.Type))
+	}
+	assert.ElementsMatch(t, []string{"A 1 DUPLICATE", "A 1 ORPHAN"}, types)
+	assert.True(t, theirs[1].LastPx.Equal(decimal.New(99, 0))) // Not changed by the correction.
+
+}
@@ -0,0 +1,304 @@
+package mkt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordingVersion is the version of the format written by [Recorder].
+//
+// A recording is the four bytes "MKTR" and the version as a uvarint, then for
+// each event the nanoseconds since the previous event, or since the Unix
+// epoch for the first, as a varint followed by a frame of the binary codec
+// holding a [Quote], [Trade] or [Report], see [CodecVersion]. So the format
+// can be streamed, does not depend on field names and is compact, with
+// repeated symbols compressing well if written through compress/gzip.
+const RecordingVersion = 1
+
+// recordingMagic starts every recording.
+const recordingMagic = "MKTR"
+
+// Recorder writes [Quote], [Trade] and [Report] events to a recording. It is
+// safe for concurrent use.
+type Recorder struct {
+	w     *bufio.Writer
+	buf   []byte
+	last  int64 // Time of the previous event.
+	clock func() time.Time
+	lock  sync.Mutex
+}
+
+// RecorderOption is any option that can be applied when constructing the
+// recorder.
+type RecorderOption func(*Recorder)
+
+// WithRecorderClock sets the source of event times. The default is [time.Now].
+func WithRecorderClock(clock func() time.Time) RecorderOption {
+	return func(recorder *Recorder) {
+		recorder.clock = clock
+	}
+}
+
+// NewRecorder writes the recording header and returns a [*Recorder] ready to
+// use. Call [Recorder.Flush] before closing the writer.
+func NewRecorder(w io.Writer, options ...RecorderOption) (*Recorder, error) {
+	recorder := &Recorder{w: bufio.NewWriter(w), clock: time.Now}
+	for _, option := range options {
+		option(recorder)
+	}
+	header := binary.AppendUvarint([]byte(recordingMagic), RecordingVersion)
+	if _, err := recorder.w.Write(header); err != nil {
+		return nil, err
+	}
+	return recorder, nil
+}
+
+// Quote records the quote.
+func (x *Recorder) Quote(quote *Quote) error {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	b, err := AppendQuote(x.begin(), quote)
+	return x.end(b, err)
+}
+
+// Trade records the trade.
+func (x *Recorder) Trade(trade *Trade) error {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	b, err := AppendTrade(x.begin(), trade)
+	return x.end(b, err)
+}
+
+// Report records the report.
+func (x *Recorder) Report(report *Report) error {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	b, err := AppendReport(x.begin(), report)
+	return x.end(b, err)
+}
+
+// Flush any buffered events to the underlying writer.
+func (x *Recorder) Flush() error {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	return x.w.Flush()
+}
+
+// begin returns the buffer holding the time of the event.
+func (x *Recorder) begin() []byte {
+	return binary.AppendVarint(x.buf[:0], x.clock().UnixNano()-x.last)
+}
+
+// end writes the event, unless it could not be encoded, in which case the
+// time of the previous event is unchanged.
+func (x *Recorder) end(b []byte, err error) error {
+	x.buf = b
+	if err != nil {
+		return err
+	}
+	delta, _ := binary.Varint(b)
+	x.last += delta
+	_, err = x.w.Write(b)
+	return err
+}
+
+// Event is one event read from a recording. Exactly one of Quote, Trade and
+// Report is set.
+type Event struct {
+	At     time.Time
+	Quote  *Quote
+	Trade  *Trade
+	Report *Report
+}
+
+// Replay reads a recording and publishes the events, in order, to the same
+// destinations that live code uses. For example:
+//
+//	replay, err := NewReplay(
+//		r,
+//		WithReplayQuotes(queue.Push),
+//		WithReplayTrades(func(trade *Trade) { c <- trade }),
+//	)
+type Replay struct {
+	r       *bufio.Reader
+	buf     []byte
+	last    int64 // Time of the previous event.
+	speed   float64
+	quotes  func(*Quote)
+	trades  func(*Trade)
+	reports func(*Report)
+	sleep   func(context.Context, time.Duration) error
+}
+
+// ReplayOption is any option that can be applied when constructing the replay.
+type ReplayOption func(*Replay)
+
+// WithReplaySpeed paces the replay at a multiple of the recorded speed, so 1
+// is real time and 10 is ten times faster. The default of zero replays as
+// fast as possible.
+func WithReplaySpeed(speed float64) ReplayOption {
+	return func(replay *Replay) {
+		replay.speed = speed
+	}
+}
+
+// WithReplayQuotes publishes each [*Quote] to the function.
+func WithReplayQuotes(publish func(*Quote)) ReplayOption {
+	return func(replay *Replay) {
+		replay.quotes = publish
+	}
+}
+
+// WithReplayTrades publishes each [*Trade] to the function.
+func WithReplayTrades(publish func(*Trade)) ReplayOption {
+	return func(replay *Replay) {
+		replay.trades = publish
+	}
+}
+
+// WithReplayReports publishes each [*Report] to the function.
+func WithReplayReports(publish func(*Report)) ReplayOption {
+	return func(replay *Replay) {
+		replay.reports = publish
+	}
+}
+
+// NewReplay reads the recording header and returns a [*Replay] ready to use.
+// It returns an error if the recording is not a version this package can read.
+func NewReplay(r io.Reader, options ...ReplayOption) (*Replay, error) {
+
+	replay := &Replay{r: bufio.NewReader(r), sleep: sleep}
+	for _, option := range options {
+		option(replay)
+	}
+
+	magic := make([]byte, len(recordingMagic))
+	if _, err := io.ReadFull(replay.r, magic); err != nil {
+		return nil, fmt.Errorf("mkt.Replay: reading header: %w", err)
+	}
+	if string(magic) != recordingMagic {
+		return nil, fmt.Errorf("mkt.Replay: unknown format %q", magic)
+	}
+	version, err := binary.ReadUvarint(replay.r)
+	if err != nil {
+		return nil, fmt.Errorf("mkt.Replay: reading header: %w", err)
+	}
+	if version != RecordingVersion {
+		return nil, fmt.Errorf("mkt.Replay: unsupported version %d", version)
+	}
+	return replay, nil
+
+}
+
+// Next returns the next event in the recording, or [io.EOF] at the end.
+func (x *Replay) Next() (*Event, error) {
+
+	delta, err := binary.ReadVarint(x.r)
+	if err != nil {
+		return nil, err
+	}
+	x.buf, err = ReadFrame(x.r, x.buf)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	x.last += delta
+	event := &Event{At: time.Unix(0, x.last).UTC()}
+
+	t, err := PeekMessageType(x.buf)
+	if err != nil {
+		return nil, err
+	}
+	switch t {
+	case MessageQuote:
+		event.Quote = &Quote{}
+		_, err = DecodeQuote(x.buf, event.Quote)
+	case MessageTrade:
+		event.Trade = &Trade{}
+		_, err = DecodeTrade(x.buf, event.Trade)
+	case MessageReport:
+		event.Report = &Report{}
+		_, err = DecodeReport(x.buf, event.Report)
+	default:
+		err = fmt.Errorf("mkt.Replay: unexpected message type %d", t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+
+}
+
+// Run publishes every remaining event, returning nil at the end of the
+// recording or the error that stopped it, including cancellation of the
+// context.
+func (x *Replay) Run(ctx context.Context) error {
+
+	var first, started time.Time
+
+	for {
+
+		event, err := x.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if x.speed > 0 {
+			if first.IsZero() {
+				first, started = event.At, time.Now()
+			}
+			due := time.Duration(float64(event.At.Sub(first)) / x.speed)
+			if err := x.sleep(ctx, due-time.Since(started)); err != nil {
+				return err
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		x.publish(event)
+
+	}
+
+}
+
+func (x *Replay) publish(event *Event) {
+	switch {
+	case event.Quote != nil:
+		if x.quotes != nil {
+			x.quotes(event.Quote)
+		}
+	case event.Trade != nil:
+		if x.trades != nil {
+			x.trades(event.Trade)
+		}
+	case event.Report != nil:
+		if x.reports != nil {
+			x.reports(event.Report)
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package mkt
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gbkr-com/utl"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordingReplay(t *testing.T) {
+
+	start := time.Date(2024, 8, 20, 8, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	var buffer bytes.Buffer
+	recorder, err := NewRecorder(&buffer, WithRecorderClock(clock))
+	assert.Nil(t, err)
+
+	assert.Nil(t, recorder.Quote(&Quote{Symbol: "A", BidPx: decimal.New(42, 0)}))
+	assert.Nil(t, recorder.Trade(&Trade{Symbol: "A", LastQty: decimal.New(10, 0), LastPx: decimal.New(42, 0)}))
+	assert.Nil(t, recorder.Quote(&Quote{Symbol: "A", BidPx: decimal.New(43, 0)}))
+	assert.Nil(t, recorder.Report(&Report{OrderID: "1", OrdStatus: OrdStatusFilled, TransactTime: start}))
+	assert.NotNil(t, recorder.Report(&Report{OrderID: "2", LastPx: decimal.RequireFromString("123456789012345678901234567890")}))
+	assert.Nil(t, recorder.Flush())
+	assert.Less(t, buffer.Len(), 100, "compact")
+
+	queue := utl.NewConflatingQueue(QuoteKey)
+	trades := make(chan *Trade, 1)
+	var reports []*Report
+	var slept []time.Duration
+
+	replay, err := NewReplay(
+		bytes.NewReader(buffer.Bytes()),
+		WithReplaySpeed(2),
+		WithReplayQuotes(queue.Push),
+		WithReplayTrades(func(trade *Trade) { trades <- trade }),
+		WithReplayReports(func(report *Report) { reports = append(reports, report) }),
+	)
+	assert.Nil(t, err)
+	replay.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d.Round(100*time.Millisecond))
+		return nil
+	}
+
+	assert.Nil(t, replay.Run(context.Background()))
+
+	quote := queue.Pop()
+	assert.True(t, quote.BidPx.Equal(decimal.New(43, 0)), "conflated")
+	trade := <-trades
+	assert.True(t, trade.LastQty.Equal(decimal.New(10, 0)))
+	assert.Equal(t, 1, len(reports))
+	assert.Equal(t, OrdStatusFilled, reports[0].OrdStatus)
+	assert.True(t, reports[0].TransactTime.Equal(start))
+
+	assert.Equal(t, []time.Duration{0, 500 * time.Millisecond, time.Second, 1500 * time.Millisecond}, slept)
+
+}
+
+func TestReplayVersion(t *testing.T) {
+
+	_, err := NewReplay(strings.NewReader("MKTR\x02"))
+	assert.ErrorContains(t, err, "version")
+	_, err = NewReplay(strings.NewReader("MKTX\x01"))
+	assert.ErrorContains(t, err, "format")
+	_, err = NewReplay(strings.NewReader(""))
+	assert.NotNil(t, err)
+
+	replay, err := NewReplay(strings.NewReader("MKTR\x01\x02"))
+	assert.Nil(t, err)
+	_, err = replay.Next()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+}
@@ -28,6 +28,9 @@ type Report struct {
 	LastPx           decimal.Decimal `json:"lastPx"`                     // FIX field 31
 	TransactTime     time.Time       `json:"transactTime"`               // FIX field 60
 	ExecInst         string          `json:"execInst,omitempty"`         // FIX field 18
+	ExecID           string          `json:"execID,omitempty"`           // FIX field 17
+	ExecType         ExecType        `json:"execType,omitempty"`         // FIX field 150
+	ExecRefID        string          `json:"execRefID,omitempty"`        // FIX field 19
 }
 
 // WorkToTarget returns true if the report indicates the originator may
@@ -35,3 +38,13 @@ type Report struct {
 func (x *Report) WorkToTarget() bool {
 	return strings.Contains(x.ExecInst, "e")
 }
+
+// ZeroReport will 'zero' all the fields in the [*Report], never returning nil.
+// This is a convenience for using reports in a [utl.Pool].
+func ZeroReport(report *Report) *Report {
+	if report == nil {
+		return &Report{}
+	}
+	*report = Report{LastQty: decimal.Zero, LastPx: decimal.Zero}
+	return report
+}
@@ -0,0 +1,157 @@
+package mkt
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Roll is the record of a futures position moved from one contract to another
+// by [Book.Roll].
+type Roll struct {
+	RollID     string          `json:"rollID"`
+	Underlying string          `json:"underlying"`
+	From       string          `json:"from"`     // Symbol closed.
+	To         string          `json:"to"`       // Symbol opened.
+	Quantity   decimal.Decimal `json:"quantity"` // Long is positive, short is negative.
+	FromPx     decimal.Decimal `json:"fromPx"`
+	ToPx       decimal.Decimal `json:"toPx"`
+	Spread     decimal.Decimal `json:"spread"` // The calendar spread, ToPx less FromPx.
+}
+
+// ChainMemo is the profit/loss across every contract on an underlying, so is
+// continuous across rolls.
+type ChainMemo struct {
+	Underlying string          `json:"underlying"`
+	Symbols    []string        `json:"symbols"` // Every contract with a position, in expiry order.
+	Quantity   decimal.Decimal `json:"quantity"`
+	Realised   decimal.Decimal `json:"realised"`
+	Unrealised decimal.Decimal `json:"unrealised"`
+	Rolls      []*Roll         `json:"rolls"`
+}
+
+// Roll closes the whole position in one futures contract at fromPx and opens
+// the same position in another at toPx. Both contracts must be whitelisted
+// futures on the same underlying. Either both trades are applied or, if this
+// function returns an error, neither.
+func (x *Book[T]) Roll(rollID, from, to string, fromPx, toPx decimal.Decimal) (*Roll, error) {
+
+	if rollID == "" {
+		return nil, errors.New("mkt.Book: empty roll ID")
+	}
+	if from == to {
+		return nil, fmt.Errorf("mkt.Book: cannot roll %s to itself", from)
+	}
+
+	underlying, err := x.underlying(from)
+	if err != nil {
+		return nil, err
+	}
+	next, err := x.underlying(to)
+	if err != nil {
+		return nil, err
+	}
+	if underlying != next {
+		return nil, fmt.Errorf("mkt.Book: %s and %s have different underlyings", from, to)
+	}
+
+	closing := x.positions[from]
+	if closing == nil || closing.quantity.IsZero() {
+		return nil, fmt.Errorf("mkt.Book: no position in %s to roll", from)
+	}
+	opening := x.positions[to]
+	if opening == nil {
+		if opening, err = x.makePosition(to); err != nil {
+			return nil, err
+		}
+	}
+
+	roll := &Roll{
+		RollID:     rollID,
+		Underlying: underlying,
+		From:       from,
+		To:         to,
+		Quantity:   closing.quantity,
+		FromPx:     fromPx,
+		ToPx:       toPx,
+		Spread:     toPx.Sub(fromPx),
+	}
+
+	side := Buy
+	if roll.Quantity.IsPositive() {
+		side = Sell
+	}
+	closing.Traded(side, roll.Quantity.Abs(), fromPx)
+	opening.Traded(side.Opposite(), roll.Quantity.Abs(), toPx)
+
+	x.rolls = append(x.rolls, roll)
+	return roll, nil
+
+}
+
+// Rolls returns every roll in the book, oldest first.
+func (x *Book[T]) Rolls() []*Roll {
+	return append([]*Roll(nil), x.rolls...)
+}
+
+// Chain returns the profit/loss across every futures position on the
+// underlying. Positions are marked at the prices given by symbol; a position
+// without a price adds nothing to the unrealised profit/loss.
+func (x *Book[T]) Chain(underlying string, marks map[string]decimal.Decimal) *ChainMemo {
+
+	memo := &ChainMemo{Underlying: underlying}
+
+	//
+	// Include positions in expired contracts, which may still have realised
+	// profit/loss after their listing has left the white list.
+	//
+	var positions []*Position[T]
+	for _, position := range x.positions {
+		def := position.definition()
+		if def == nil || def.Instrument == nil || def.Instrument.SecurityType != Future || def.Instrument.Underlying != underlying {
+			continue
+		}
+		positions = append(positions, position)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		a, b := positions[i].definition().Instrument.Expiry, positions[j].definition().Instrument.Expiry
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return positions[i].symbol < positions[j].symbol
+	})
+
+	for _, position := range positions {
+		memo.Symbols = append(memo.Symbols, position.symbol)
+		memo.Quantity = memo.Quantity.Add(position.quantity)
+		memo.Realised = memo.Realised.Add(position.realised)
+		if price, ok := marks[position.symbol]; ok {
+			_, unrealised := position.Mark(price)
+			memo.Unrealised = memo.Unrealised.Add(unrealised)
+		}
+	}
+
+	for _, roll := range x.rolls {
+		if roll.Underlying == underlying {
+			memo.Rolls = append(memo.Rolls, roll)
+		}
+	}
+
+	return memo
+
+}
+
+// underlying returns the underlying of a whitelisted futures contract.
+func (x *Book[T]) underlying(symbol string) (string, error) {
+	listing, ok := x.whitelist.Lookup(symbol)
+	if !ok {
+		return "", fmt.Errorf("mkt.Book: %s is not whitelisted", symbol)
+	}
+	instrument := listing.Definition().Instrument
+	if instrument == nil || instrument.SecurityType != Future || instrument.Underlying == "" {
+		return "", fmt.Errorf("mkt.Book: %s is not a future with an underlying", symbol)
+	}
+	return instrument.Underlying, nil
+}
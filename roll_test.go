@@ -0,0 +1,69 @@
+package mkt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookRoll(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(testFuture("ESZ6", time.Date(2026, 12, 18, 14, 30, 0, 0, time.UTC)))
+	whitelist.Add(testFuture("ESH7", time.Date(2027, 3, 19, 14, 30, 0, 0, time.UTC)))
+	nq := testFuture("NQZ6", time.Date(2026, 12, 18, 14, 30, 0, 0, time.UTC))
+	nq.Instrument.Underlying = "NDX"
+	whitelist.Add(nq)
+	whitelist.Add(&Listing{Symbol: "A"})
+
+	memos := make(chan *PositionMemo, 16)
+	book := NewBook("FUT", whitelist, WithBookChannel[*Listing](memos))
+
+	_, err := book.Roll("R1", "ESZ6", "ESH7", decimal.New(5010, 0), decimal.New(5020, 0))
+	assert.NotNil(t, err, "no position")
+
+	assert.Nil(t, book.Traded("ESZ6", Buy, decimal.New(2, 0), decimal.New(5000, 0)))
+	<-memos
+
+	_, err = book.Roll("R1", "ESZ6", "NQZ6", decimal.New(5010, 0), decimal.New(5020, 0))
+	assert.NotNil(t, err, "different underlying")
+	_, err = book.Roll("R1", "ESZ6", "A", decimal.New(5010, 0), decimal.New(5020, 0))
+	assert.NotNil(t, err, "not a future")
+	assert.Equal(t, 0, len(memos))
+
+	roll, err := book.Roll("R1", "ESZ6", "ESH7", decimal.New(5010, 0), decimal.New(5020, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, "SPX", roll.Underlying)
+	assert.True(t, roll.Quantity.Equal(decimal.New(2, 0)))
+	assert.True(t, roll.Spread.Equal(decimal.New(10, 0)))
+	assert.Equal(t, 1, len(book.Rolls()))
+
+	closed := <-memos
+	assert.Equal(t, "ESZ6", closed.Symbol)
+	assert.True(t, closed.Quantity.IsZero())
+	assert.True(t, closed.Realised.Equal(decimal.New(1000, 0)))
+	opened := <-memos
+	assert.Equal(t, "ESH7", opened.Symbol)
+	assert.True(t, opened.Quantity.Equal(decimal.New(2, 0)))
+
+	chain := book.Chain("SPX", map[string]decimal.Decimal{"ESH7": decimal.New(5030, 0)})
+	assert.Equal(t, []string{"ESZ6", "ESH7"}, chain.Symbols)
+	assert.True(t, chain.Quantity.Equal(decimal.New(2, 0)))
+	assert.True(t, chain.Realised.Equal(decimal.New(1000, 0)))
+	assert.True(t, chain.Unrealised.Equal(decimal.New(1000, 0)))
+	assert.Equal(t, 1, len(chain.Rolls))
+
+	assert.Equal(t, 0, len(book.Chain("NDX", nil).Symbols))
+
+	//
+	// The expired contract still counts once it has left the white list.
+	//
+	whitelist.Remove("ESZ6")
+	chain = book.Chain("SPX", map[string]decimal.Decimal{"ESH7": decimal.New(5030, 0)})
+	assert.Equal(t, []string{"ESZ6", "ESH7"}, chain.Symbols)
+	assert.True(t, chain.Realised.Equal(decimal.New(1000, 0)))
+	assert.True(t, chain.Unrealised.Equal(decimal.New(1000, 0)))
+
+}
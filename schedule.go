@@ -0,0 +1,165 @@
+package mkt
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Slice is one child order in a [Schedule]: the quantity to send at the time.
+type Slice struct {
+	Time     time.Time       `json:"time"`
+	OrderQty decimal.Decimal `json:"orderQty"`
+}
+
+// Schedule is a sequence of [Slice] in time order.
+type Schedule []Slice
+
+// Total returns the quantity of all the slices.
+func (x Schedule) Total() decimal.Decimal {
+	total := decimal.Zero
+	for _, slice := range x {
+		total = total.Add(slice.OrderQty)
+	}
+	return total
+}
+
+// Due returns the quantity of the slices at or before the time. An algorithm
+// following the schedule sends the difference between this and the quantity
+// already filled or working, such as [Parent.CumQty] plus [Parent.LiveQty].
+func (x Schedule) Due(at time.Time) decimal.Decimal {
+	due := decimal.Zero
+	for _, slice := range x {
+		if slice.Time.After(at) {
+			break
+		}
+		due = due.Add(slice.OrderQty)
+	}
+	return due
+}
+
+// Slicer divides a parent order quantity into a [Schedule] over a time
+// window, which is split into equal intervals each with a weight. Use
+// [NewTWAP] or [NewVWAP].
+//
+// The cumulative quantity due by each interval is in proportion to the
+// cumulative weight. Each slice is the quantity due less that already
+// scheduled, rounded down to the RoundLot of the listing and dropped if below
+// its MinTradeVol, so any remainder is carried forward to the next interval.
+// Whatever cannot be sent in whole lots by the last interval is left out of
+// the schedule.
+//
+// The window starts at the time of the clock when [Slicer.Schedule] is
+// called. Slices are at the start of each interval unless there is jitter, in
+// which case the same clock and seed always give the same schedule.
+type Slicer[T AnyListing] struct {
+	listing T
+	window  time.Duration
+	weights []decimal.Decimal
+	clock   func() time.Time
+	jitter  float64
+	seed    int64
+}
+
+// SlicerOption is any option that can be applied when constructing the
+// slicer.
+type SlicerOption[T AnyListing] func(*Slicer[T])
+
+// WithSlicerClock sets the source of the start time. The default is
+// [time.Now].
+func WithSlicerClock[T AnyListing](clock func() time.Time) SlicerOption[T] {
+	return func(x *Slicer[T]) {
+		x.clock = clock
+	}
+}
+
+// WithSlicerJitter moves each slice later into its interval by a random
+// fraction of the interval, up to the given fraction, so that the schedule is
+// less predictable to the market. The random numbers come from the seed.
+func WithSlicerJitter[T AnyListing](fraction float64, seed int64) SlicerOption[T] {
+	return func(x *Slicer[T]) {
+		x.jitter = min(max(fraction, 0), 1)
+		x.seed = seed
+	}
+}
+
+// NewTWAP returns a [*Slicer] that divides the quantity evenly over n
+// intervals of the window.
+func NewTWAP[T AnyListing](listing T, window time.Duration, n int, options ...SlicerOption[T]) *Slicer[T] {
+	weights := make([]decimal.Decimal, max(n, 0))
+	for i := range weights {
+		weights[i] = DecimalOne
+	}
+	return newSlicer(listing, window, weights, options)
+}
+
+// NewVWAP returns a [*Slicer] that divides the quantity in proportion to the
+// volume profile, such as the historical volume traded in each interval of the
+// window. Negative volumes are taken as zero.
+func NewVWAP[T AnyListing](listing T, window time.Duration, profile []decimal.Decimal, options ...SlicerOption[T]) *Slicer[T] {
+	weights := make([]decimal.Decimal, len(profile))
+	for i, volume := range profile {
+		weights[i] = decimal.Max(volume, decimal.Zero)
+	}
+	return newSlicer(listing, window, weights, options)
+}
+
+func newSlicer[T AnyListing](listing T, window time.Duration, weights []decimal.Decimal, options []SlicerOption[T]) *Slicer[T] {
+	slicer := &Slicer[T]{
+		listing: listing,
+		window:  window,
+		weights: weights,
+		clock:   time.Now,
+	}
+	for _, option := range options {
+		option(slicer)
+	}
+	return slicer
+}
+
+// Schedule returns the schedule for the quantity, starting now.
+func (x *Slicer[T]) Schedule(qty decimal.Decimal) Schedule {
+
+	total := decimal.Zero
+	for _, weight := range x.weights {
+		total = total.Add(weight)
+	}
+	if !qty.IsPositive() || !total.IsPositive() {
+		return nil
+	}
+
+	def := x.listing.Definition()
+	start := x.clock()
+	interval := x.window / time.Duration(len(x.weights))
+	random := rand.New(rand.NewSource(x.seed))
+
+	var schedule Schedule
+	cumWeight, scheduled := decimal.Zero, decimal.Zero
+	for i, weight := range x.weights {
+
+		//
+		// Draw for every interval, so that each slice time does not depend on
+		// which other intervals have a slice.
+		//
+		offset := time.Duration(float64(interval) * x.jitter * random.Float64())
+
+		cumWeight = cumWeight.Add(weight)
+		due := qty
+		if i < len(x.weights)-1 {
+			due = qty.Mul(cumWeight).Div(total)
+		}
+		orderQty := Units(due.Sub(scheduled), def.RoundLot, def.MinTradeVol)
+		if orderQty.IsZero() {
+			continue
+		}
+		scheduled = scheduled.Add(orderQty)
+		schedule = append(schedule, Slice{
+			Time:     start.Add(time.Duration(i)*interval + offset),
+			OrderQty: orderQty,
+		})
+
+	}
+	return schedule
+
+}
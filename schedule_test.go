@@ -0,0 +1,88 @@
+package mkt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testClock() time.Time {
+	return time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+}
+
+func testQuantities(schedule Schedule) []string {
+	var quantities []string
+	for _, slice := range schedule {
+		quantities = append(quantities, slice.OrderQty.String())
+	}
+	return quantities
+}
+
+func TestTWAP(t *testing.T) {
+
+	listing := &Listing{Symbol: "A", RoundLot: decimal.New(100, 0), MinTradeVol: decimal.New(200, 0)}
+	slicer := NewTWAP(listing, time.Hour, 4, WithSlicerClock[*Listing](testClock))
+
+	schedule := slicer.Schedule(decimal.New(1000, 0))
+	assert.Equal(t, []string{"200", "300", "200", "300"}, testQuantities(schedule))
+	assert.Equal(t, testClock(), schedule[0].Time)
+	assert.Equal(t, testClock().Add(45*time.Minute), schedule[3].Time)
+	assert.True(t, schedule.Total().Equal(decimal.New(1000, 0)))
+
+	assert.True(t, schedule.Due(testClock().Add(-time.Second)).IsZero())
+	assert.True(t, schedule.Due(testClock().Add(15*time.Minute)).Equal(decimal.New(500, 0)))
+	assert.True(t, schedule.Due(testClock().Add(2*time.Hour)).Equal(decimal.New(1000, 0)))
+
+	//
+	// Too little for each interval is carried forward, and an odd lot is left
+	// out.
+	//
+	schedule = slicer.Schedule(decimal.New(450, 0))
+	assert.Equal(t, []string{"200", "200"}, testQuantities(schedule))
+	assert.Equal(t, testClock().Add(15*time.Minute), schedule[0].Time)
+	assert.Equal(t, testClock().Add(45*time.Minute), schedule[1].Time)
+
+	assert.Nil(t, slicer.Schedule(decimal.Zero))
+	assert.Nil(t, NewTWAP(listing, time.Hour, 0).Schedule(decimal.New(1000, 0)))
+
+}
+
+func TestVWAP(t *testing.T) {
+
+	listing := &Listing{Symbol: "A", RoundLot: decimal.New(10, 0)}
+	profile := []decimal.Decimal{decimal.New(5, 0), decimal.New(2, 0), decimal.Zero, decimal.New(-1, 0), decimal.New(3, 0)}
+	slicer := NewVWAP(listing, 50*time.Minute, profile, WithSlicerClock[*Listing](testClock))
+
+	schedule := slicer.Schedule(decimal.New(1000, 0))
+	assert.Equal(t, []string{"500", "200", "300"}, testQuantities(schedule))
+	assert.Equal(t, testClock().Add(40*time.Minute), schedule[2].Time)
+
+	schedule = slicer.Schedule(decimal.New(105, 0))
+	assert.Equal(t, []string{"50", "20", "30"}, testQuantities(schedule))
+
+}
+
+func TestSlicerJitter(t *testing.T) {
+
+	listing := &Listing{Symbol: "A", RoundLot: DecimalOne}
+	options := []SlicerOption[*Listing]{WithSlicerClock[*Listing](testClock), WithSlicerJitter[*Listing](0.5, 42)}
+
+	first := NewTWAP(listing, time.Hour, 6, options...).Schedule(decimal.New(60, 0))
+	second := NewTWAP(listing, time.Hour, 6, options...).Schedule(decimal.New(60, 0))
+	assert.Equal(t, first, second)
+
+	jittered := false
+	for i, slice := range first {
+		start := testClock().Add(time.Duration(i) * 10 * time.Minute)
+		assert.False(t, slice.Time.Before(start))
+		assert.True(t, slice.Time.Before(start.Add(5*time.Minute)))
+		jittered = jittered || slice.Time.After(start)
+	}
+	assert.True(t, jittered)
+
+	other := NewTWAP(listing, time.Hour, 6, WithSlicerClock[*Listing](testClock), WithSlicerJitter[*Listing](0.5, 7)).Schedule(decimal.New(60, 0))
+	assert.NotEqual(t, first, other)
+
+}
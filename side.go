@@ -2,6 +2,7 @@ package mkt
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/quickfixgo/enum"
 	"github.com/quickfixgo/field"
@@ -73,6 +74,57 @@ func (x Side) Improve(price, increment decimal.Decimal) decimal.Decimal {
 	}
 }
 
+// RoundPassive returns the price rounded to a whole tick of the listing away
+// from the market: down when buying and up when selling. A passive price never
+// pays more, or receives less, than the price given.
+func (x Side) RoundPassive(price decimal.Decimal, listing *Listing) decimal.Decimal {
+	switch x {
+	case Buy:
+		return listing.RoundPrice(price)
+	case Sell:
+		return listing.roundPriceUp(price)
+	default:
+		return price
+	}
+}
+
+// RoundAggressive returns the price rounded to a whole tick of the listing
+// towards the market: up when buying and down when selling.
+func (x Side) RoundAggressive(price decimal.Decimal, listing *Listing) decimal.Decimal {
+	switch x {
+	case Buy:
+		return listing.roundPriceUp(price)
+	case Sell:
+		return listing.RoundPrice(price)
+	default:
+		return price
+	}
+}
+
+// ImproveTicks returns the price improved by n whole ticks of the listing, in
+// the same direction as [Side.Improve]. The price is first rounded passively.
+func (x Side) ImproveTicks(price decimal.Decimal, n int64, listing *Listing) decimal.Decimal {
+	switch x {
+	case Buy:
+		return listing.TickUp(listing.RoundPrice(price), n)
+	case Sell:
+		return listing.TickDown(listing.roundPriceUp(price), n)
+	default:
+		return price
+	}
+}
+
+// ImproveTicksWithin returns the price improved as [Side.ImproveTicks] but no
+// further than the limit, rounded passively. A zero limit does not constrain
+// the price.
+func (x Side) ImproveTicksWithin(price decimal.Decimal, n int64, limit decimal.Decimal, listing *Listing) decimal.Decimal {
+	improved := x.ImproveTicks(price, n, listing)
+	if x.Within(improved, limit) {
+		return improved
+	}
+	return x.RoundPassive(limit, listing)
+}
+
 func (x Side) String() string {
 	switch x {
 	case Buy:
@@ -96,6 +148,20 @@ func SideFromString(x string) Side {
 	}
 }
 
+// ParseSide returns the [Side] for the mnemonic, which is also the long name,
+// or the FIX code. Unlike [SideFromString] it returns an error for any other
+// value.
+func ParseSide(s string) (Side, error) {
+	switch s {
+	case "BUY", string(enum.Side_BUY):
+		return Buy, nil
+	case "SELL", string(enum.Side_SELL):
+		return Sell, nil
+	default:
+		return 0, fmt.Errorf("mkt.ParseSide: unknown value %q", s)
+	}
+}
+
 // MarshalJSON implements [json.Marshaler].
 func (x Side) MarshalJSON() ([]byte, error) {
 	return json.Marshal(x.String())
@@ -124,3 +190,16 @@ func (x Side) AsQuickFIX() field.SideField {
 		return field.NewSide(enum.Side_UNDISCLOSED)
 	}
 }
+
+// SideFromFIX returns the equivalent [Side] from the QuickFIX field, or zero if
+// there is no equivalence.
+func SideFromFIX(side field.SideField) Side {
+	switch side.Value() {
+	case enum.Side_BUY:
+		return Buy
+	case enum.Side_SELL:
+		return Sell
+	default:
+		return 0
+	}
+}
@@ -23,6 +23,39 @@ func TestSideImprove(t *testing.T) {
 
 }
 
+func TestSideRound(t *testing.T) {
+
+	listing := &Listing{TickIncrement: decimal.New(5, -1)}
+	PRICE := decimal.New(4213, -2)
+
+	assert.True(t, Buy.RoundPassive(PRICE, listing).Equal(decimal.New(42, 0)))
+	assert.True(t, Sell.RoundPassive(PRICE, listing).Equal(decimal.New(425, -1)))
+	assert.True(t, Buy.RoundAggressive(PRICE, listing).Equal(decimal.New(425, -1)))
+	assert.True(t, Sell.RoundAggressive(PRICE, listing).Equal(decimal.New(42, 0)))
+
+	assert.True(t, Buy.RoundPassive(decimal.New(42, 0), listing).Equal(decimal.New(42, 0)))
+	assert.True(t, Sell.RoundPassive(decimal.New(42, 0), listing).Equal(decimal.New(42, 0)))
+
+}
+
+func TestSideImproveTicks(t *testing.T) {
+
+	listing := &Listing{TickIncrement: decimal.New(5, -1)}
+	PRICE := decimal.New(4213, -2)
+
+	assert.True(t, Buy.ImproveTicks(PRICE, 2, listing).Equal(decimal.New(43, 0)))
+	assert.True(t, Sell.ImproveTicks(PRICE, 2, listing).Equal(decimal.New(415, -1)))
+
+	LIMIT := decimal.New(4260, -2)
+	assert.True(t, Buy.ImproveTicksWithin(PRICE, 1, LIMIT, listing).Equal(decimal.New(425, -1)))
+	assert.True(t, Buy.ImproveTicksWithin(PRICE, 3, LIMIT, listing).Equal(decimal.New(425, -1)))
+	assert.True(t, Buy.ImproveTicksWithin(PRICE, 3, decimal.Zero, listing).Equal(decimal.New(435, -1)))
+
+	LIMIT = decimal.New(4140, -2)
+	assert.True(t, Sell.ImproveTicksWithin(PRICE, 4, LIMIT, listing).Equal(decimal.New(415, -1)))
+
+}
+
 func TestSideWithin(t *testing.T) {
 
 	PLUS := decimal.New(425, -1)
@@ -61,3 +94,9 @@ func TestSideJSON(t *testing.T) {
 	assert.Equal(t, Side(0), side)
 
 }
+
+func TestSideFromFIX(t *testing.T) {
+	assert.Equal(t, Buy, SideFromFIX(Buy.AsQuickFIX()))
+	assert.Equal(t, Sell, SideFromFIX(Sell.AsQuickFIX()))
+	assert.Equal(t, Side(0), SideFromFIX(Side(0).AsQuickFIX()))
+}
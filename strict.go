@@ -0,0 +1,264 @@
+package mkt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// StrictDecoder decodes JSON as [json.Decoder] does, except that the enum
+// types [Side], [OrdStatus], [TimeInForce], [MsgType] and [ExecType] are
+// decoded with their Parse functions, such as [ParseSide]. So an unknown value
+// is an error, rather than zero, and the long names and FIX codes are accepted
+// as well as the mnemonics. The empty string is still zero, since that is how
+// a zero enum is marshalled.
+//
+// Strictness is chosen per decoder: a [json.Decoder], or [json.Unmarshal],
+// remains lenient.
+type StrictDecoder struct {
+	dec *json.Decoder
+}
+
+// NewStrictDecoder returns a [*StrictDecoder] reading from r.
+func NewStrictDecoder(r io.Reader) *StrictDecoder {
+	return &StrictDecoder{dec: json.NewDecoder(r)}
+}
+
+// Decode reads the next JSON value into v, which must be a non-nil pointer.
+func (x *StrictDecoder) Decode(v any) error {
+	var raw json.RawMessage
+	if err := x.dec.Decode(&raw); err != nil {
+		return err
+	}
+	return UnmarshalStrict(raw, v)
+}
+
+// More reports whether there is another value to decode.
+func (x *StrictDecoder) More() bool {
+	return x.dec.More()
+}
+
+// UnmarshalStrict is [json.Unmarshal] with the strictness of [StrictDecoder].
+func UnmarshalStrict(data []byte, v any) error {
+
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return errors.New("mkt.UnmarshalStrict: target is not a non-nil pointer")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	//
+	// The lenient decode has set everything but the enums, which are now set
+	// again from the original values.
+	//
+	var tree any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return err
+	}
+	if err := strictWalk(target.Elem(), tree, "$"); err != nil {
+		return fmt.Errorf("mkt.UnmarshalStrict: %w", err)
+	}
+	return nil
+
+}
+
+// strictEnum is implemented by the enum types that are decoded strictly.
+type strictEnum interface {
+	parseStrict(s string) error
+}
+
+func (x *Side) parseStrict(s string) (err error) {
+	*x, err = ParseSide(s)
+	return
+}
+
+func (x *OrdStatus) parseStrict(s string) (err error) {
+	*x, err = ParseOrdStatus(s)
+	return
+}
+
+func (x *TimeInForce) parseStrict(s string) (err error) {
+	*x, err = ParseTimeInForce(s)
+	return
+}
+
+func (x *MsgType) parseStrict(s string) (err error) {
+	*x, err = ParseMsgType(s)
+	return
+}
+
+func (x *ExecType) parseStrict(s string) (err error) {
+	*x, err = ParseExecType(s)
+	return
+}
+
+var (
+	strictEnumType  = reflect.TypeFor[strictEnum]()
+	unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// strictWalk visits the value alongside the generic decoding of its JSON,
+// decoding every enum strictly.
+func strictWalk(v reflect.Value, raw any, path string) error {
+
+	if raw == nil {
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(strictEnumType) {
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a string", path, raw)
+		}
+		//
+		// A zero enum marshals as the empty string, so that is not an error.
+		//
+		if s == "" {
+			v.SetZero()
+			return nil
+		}
+		if err := v.Addr().Interface().(strictEnum).parseStrict(s); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		return nil
+	}
+
+	switch v.Kind() {
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return strictWalk(v.Elem(), raw, path)
+
+	case reflect.Struct:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for _, f := range jsonFields(v.Type()) {
+			value, ok := lookupKey(object, f.name)
+			if !ok {
+				continue
+			}
+			field, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				continue
+			}
+			if err := strictWalk(field, value, path+"."+f.name); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		array, ok := raw.([]any)
+		if !ok {
+			return nil
+		}
+		for i := 0; i < v.Len() && i < len(array); i++ {
+			if err := strictWalk(v.Index(i), array[i], path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		object, ok := raw.(map[string]any)
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		for _, key := range v.MapKeys() {
+			//
+			// Map elements are not addressable, so are walked as copies.
+			//
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			if err := strictWalk(elem, object[key.String()], path+"."+key.String()); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+
+	}
+
+	return nil
+
+}
+
+// lookupKey finds the key as [json.Unmarshal] does, preferring an exact
+// match to a case-insensitive one.
+func lookupKey(object map[string]any, name string) (any, bool) {
+	if value, ok := object[name]; ok {
+		return value, true
+	}
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+type jsonField struct {
+	name  string
+	index []int
+}
+
+var jsonFieldCache sync.Map // reflect.Type to []jsonField
+
+// jsonFields returns the JSON names of the fields of the struct type,
+// including those promoted from embedded structs.
+func jsonFields(t reflect.Type) []jsonField {
+
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.([]jsonField)
+	}
+
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for _, promoted := range jsonFields(embedded) {
+					fields = append(fields, jsonField{name: promoted.name, index: append([]int{i}, promoted.index...)})
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, jsonField{name: name, index: []int{i}})
+
+	}
+
+	jsonFieldCache.Store(t, fields)
+	return fields
+
+}
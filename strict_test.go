@@ -0,0 +1,143 @@
+package mkt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEnums(t *testing.T) {
+
+	side, err := ParseSide("BUY")
+	assert.Nil(t, err)
+	assert.Equal(t, Buy, side)
+	side, err = ParseSide("2")
+	assert.Nil(t, err)
+	assert.Equal(t, Sell, side)
+	_, err = ParseSide("BYU")
+	assert.ErrorContains(t, err, `"BYU"`)
+
+	status, err := ParseOrdStatus("FILL")
+	assert.Nil(t, err)
+	assert.Equal(t, OrdStatusPartiallyFilled, status)
+	status, err = ParseOrdStatus("PARTIALLY_FILLED")
+	assert.Nil(t, err)
+	assert.Equal(t, OrdStatusPartiallyFilled, status)
+	status, err = ParseOrdStatus("2")
+	assert.Nil(t, err)
+	assert.Equal(t, OrdStatusFilled, status)
+	_, err = ParseOrdStatus("X")
+	assert.NotNil(t, err)
+
+	tif, err := ParseTimeInForce("IMMEDIATE_OR_CANCEL")
+	assert.Nil(t, err)
+	assert.Equal(t, IOC, tif)
+	tif, err = ParseTimeInForce("1")
+	assert.Nil(t, err)
+	assert.Equal(t, GTC, tif)
+	_, err = ParseTimeInForce("DAY")
+	assert.NotNil(t, err)
+
+	msgType, err := ParseMsgType("F")
+	assert.Nil(t, err)
+	assert.Equal(t, OrderCancel, msgType)
+	msgType, err = ParseMsgType("ORDER_CANCEL_REPLACE_REQUEST")
+	assert.Nil(t, err)
+	assert.Equal(t, OrderReplace, msgType)
+	_, err = ParseMsgType("Z")
+	assert.NotNil(t, err)
+
+	execType, err := ParseExecType("H")
+	assert.Nil(t, err)
+	assert.Equal(t, ExecTypeTradeCancel, execType)
+	execType, err = ParseExecType("TRADE_CORRECT")
+	assert.Nil(t, err)
+	assert.Equal(t, ExecTypeTradeCorrect, execType)
+	execType, err = ParseExecType("2")
+	assert.Nil(t, err)
+	assert.Equal(t, ExecTypeTrade, execType)
+	_, err = ParseExecType("FILL")
+	assert.NotNil(t, err)
+
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+
+	var ticket Ticket
+	err := UnmarshalStrict([]byte(`{"msgType":"D","side":"1","symbol":"A","orderQty":"10","timeInForce":"IOC"}`), &ticket)
+	assert.Nil(t, err)
+	assert.Equal(t, OrderNew, ticket.MsgType)
+	assert.Equal(t, Buy, ticket.Side)
+	assert.Equal(t, "A", ticket.Symbol)
+	assert.True(t, ticket.OrderQty.Equal(decimal.New(10, 0)))
+	assert.Equal(t, IOC, ticket.TimeInForce)
+
+	//
+	// The lenient decoder turns a typo into zero; the strict one does not.
+	//
+	BAD := []byte(`{"msgType":"NEW","side":"BYU","symbol":"A"}`)
+	var order Order
+	assert.Nil(t, json.Unmarshal(BAD, &order))
+	assert.Equal(t, Side(0), order.Side)
+	err = UnmarshalStrict(BAD, &order)
+	assert.ErrorContains(t, err, "$.side")
+	assert.ErrorContains(t, err, `"BYU"`)
+
+	err = UnmarshalStrict([]byte(`{"side":1}`), &order)
+	assert.NotNil(t, err)
+
+	//
+	// The empty string is how a zero enum marshals.
+	//
+	order = Order{}
+	b, err := json.Marshal(order)
+	assert.Nil(t, err)
+	assert.Nil(t, UnmarshalStrict(b, &order))
+
+	assert.NotNil(t, UnmarshalStrict([]byte(`{}`), order))
+
+}
+
+func TestUnmarshalStrictNested(t *testing.T) {
+
+	type batch struct {
+		Reports []*Report         `json:"reports"`
+		Latest  map[string]Report `json:"latest"`
+	}
+
+	var x batch
+	err := UnmarshalStrict([]byte(`{
+		"reports": [{"orderID":"1","ordStatus":"NEW"},{"orderID":"2","ordStatus":"PARTIALLY_FILLED","side":"SELL"}],
+		"latest": {"A": {"orderID":"3","ordStatus":"4"}}
+	}`), &x)
+	assert.Nil(t, err)
+	assert.Equal(t, OrdStatusNew, x.Reports[0].OrdStatus)
+	assert.Equal(t, OrdStatusPartiallyFilled, x.Reports[1].OrdStatus)
+	assert.Equal(t, Sell, x.Reports[1].Side)
+	assert.Equal(t, OrdStatusCanceled, x.Latest["A"].OrdStatus)
+
+	err = UnmarshalStrict([]byte(`{"reports": [{"orderID":"1"},{"orderID":"2","ordStatus":"FINISHED"}]}`), &x)
+	assert.ErrorContains(t, err, "$.reports[1].ordStatus")
+
+	err = UnmarshalStrict([]byte(`{"latest": {"A": {"timeInForce":"GTD"}}}`), &x)
+	assert.ErrorContains(t, err, "$.latest.A.timeInForce")
+
+}
+
+func TestStrictDecoder(t *testing.T) {
+
+	dec := NewStrictDecoder(strings.NewReader(`{"side":"BUY"} {"side":"SELL"} {"side":"HOLD"}`))
+
+	var order Order
+	assert.True(t, dec.More())
+	assert.Nil(t, dec.Decode(&order))
+	assert.Equal(t, Buy, order.Side)
+	assert.Nil(t, dec.Decode(&order))
+	assert.Equal(t, Sell, order.Side)
+	assert.NotNil(t, dec.Decode(&order))
+	assert.False(t, dec.More())
+
+}
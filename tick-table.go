@@ -0,0 +1,212 @@
+package mkt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// TickBand is the tick increment for prices at or above From, up to the From
+// of the next band.
+type TickBand struct {
+	From      decimal.Decimal `json:"from"`
+	Increment decimal.Decimal `json:"increment"`
+}
+
+// TickTable is a price-banded tick size regime, such as the MiFID II tick
+// size tables. Ticks within a band are counted from the From of that band.
+//
+// A TickTable is immutable once constructed, so may be shared between
+// listings.
+type TickTable struct {
+	bands []TickBand // Ascending From.
+}
+
+// NewTickTable returns a [*TickTable] for the bands, which must have
+// ascending From values and positive increments. The first band also applies
+// to any price below its From.
+func NewTickTable(bands ...TickBand) (*TickTable, error) {
+	if len(bands) == 0 {
+		return nil, errors.New("mkt.NewTickTable: no bands")
+	}
+	for i, band := range bands {
+		if !band.Increment.IsPositive() {
+			return nil, fmt.Errorf("mkt.NewTickTable: band %d: increment %s is not positive", i, band.Increment)
+		}
+		if i > 0 && !band.From.GreaterThan(bands[i-1].From) {
+			return nil, fmt.Errorf("mkt.NewTickTable: band %d: From %s is not ascending", i, band.From)
+		}
+	}
+	return &TickTable{bands: append([]TickBand(nil), bands...)}, nil
+}
+
+// Bands returns a copy of the bands in the table.
+func (x *TickTable) Bands() []TickBand {
+	return append([]TickBand(nil), x.bands...)
+}
+
+// Increment returns the tick increment at the price.
+func (x *TickTable) Increment(price decimal.Decimal) decimal.Decimal {
+	return x.bands[x.band(price)].Increment
+}
+
+// Precision returns the number of decimals in the finest increment.
+func (x *TickTable) Precision() int32 {
+	var precision int32
+	for _, band := range x.bands {
+		if p := Precision(band.Increment); p > precision {
+			precision = p
+		}
+	}
+	return precision
+}
+
+// Round returns the price rounded down to a whole tick. Below the From of the
+// first band, including negative prices, ticks continue down from that From.
+func (x *TickTable) Round(price decimal.Decimal) decimal.Decimal {
+	band := x.bands[x.band(price)]
+	return price.Sub(floorMod(price.Sub(band.From), band.Increment))
+}
+
+// RoundUp returns the price rounded up to a whole tick.
+func (x *TickTable) RoundUp(price decimal.Decimal) decimal.Decimal {
+	rounded := x.Round(price)
+	if rounded.Equal(price) {
+		return price
+	}
+	return x.Up(rounded, 1)
+}
+
+// Up returns the price moved up by n whole ticks, crossing into higher bands
+// as necessary. The price is first rounded down to a whole tick.
+func (x *TickTable) Up(price decimal.Decimal, n int64) decimal.Decimal {
+
+	if n < 0 {
+		return x.Down(price, -n)
+	}
+
+	price = x.Round(price)
+	i := x.band(price)
+
+	for n > 0 {
+		increment := x.bands[i].Increment
+		if i == len(x.bands)-1 {
+			return price.Add(increment.Mul(decimal.New(n, 0)))
+		}
+		next := x.bands[i+1].From
+		available := next.Sub(price).Div(increment).Ceil().IntPart()
+		if n < available {
+			return price.Add(increment.Mul(decimal.New(n, 0)))
+		}
+		price, n, i = next, n-available, i+1
+	}
+	return price
+
+}
+
+// Down returns the price moved down by n whole ticks, crossing into lower
+// bands as necessary. The price is first rounded up to a whole tick.
+func (x *TickTable) Down(price decimal.Decimal, n int64) decimal.Decimal {
+
+	if n < 0 {
+		return x.Up(price, -n)
+	}
+
+	price = x.RoundUp(price)
+	i := x.band(price)
+
+	for n > 0 {
+		from := x.bands[i].From
+		if i == 0 || price.GreaterThan(from) {
+			increment := x.bands[i].Increment
+			if i == 0 {
+				return price.Sub(increment.Mul(decimal.New(n, 0)))
+			}
+			available := price.Sub(from).Div(increment).IntPart()
+			if n <= available {
+				return price.Sub(increment.Mul(decimal.New(n, 0)))
+			}
+			price, n = from, n-available
+			continue
+		}
+		//
+		// At the From of a band, the tick below is that of the band below.
+		//
+		i--
+		below := x.bands[i]
+		price = price.Sub(price.Sub(below.From).Mod(below.Increment))
+		if price.Equal(from) {
+			price = price.Sub(below.Increment)
+		}
+		n--
+	}
+	return price
+
+}
+
+// Ticks returns the signed number of whole ticks from one price to another,
+// both of which should be whole ticks.
+func (x *TickTable) Ticks(from, to decimal.Decimal) int64 {
+
+	if from.Equal(to) {
+		return 0
+	}
+	if from.GreaterThan(to) {
+		return -x.Ticks(to, from)
+	}
+
+	var ticks int64
+	i := x.band(from)
+	for {
+		increment := x.bands[i].Increment
+		if i == len(x.bands)-1 || to.LessThanOrEqual(x.bands[i+1].From) {
+			return ticks + to.Sub(from).Div(increment).Ceil().IntPart()
+		}
+		next := x.bands[i+1].From
+		ticks += next.Sub(from).Div(increment).Ceil().IntPart()
+		from, i = next, i+1
+	}
+
+}
+
+// floorMod returns the remainder of x divided by the positive y, which unlike
+// [decimal.Decimal.Mod] is never negative, so that subtracting it always
+// rounds down.
+func floorMod(x, y decimal.Decimal) decimal.Decimal {
+	remainder := x.Mod(y)
+	if remainder.IsNegative() {
+		return remainder.Add(y)
+	}
+	return remainder
+}
+
+// band returns the index of the band for the price.
+func (x *TickTable) band(price decimal.Decimal) int {
+	for i := len(x.bands) - 1; i > 0; i-- {
+		if price.GreaterThanOrEqual(x.bands[i].From) {
+			return i
+		}
+	}
+	return 0
+}
+
+// MarshalJSON implements [json.Marshaler] as an array of bands.
+func (x *TickTable) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.bands)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], validating as [NewTickTable].
+func (x *TickTable) UnmarshalJSON(b []byte) error {
+	var bands []TickBand
+	if err := json.Unmarshal(b, &bands); err != nil {
+		return err
+	}
+	table, err := NewTickTable(bands...)
+	if err != nil {
+		return err
+	}
+	*x = *table
+	return nil
+}
@@ -0,0 +1,148 @@
+package mkt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTickTable(t *testing.T) *TickTable {
+	table, err := NewTickTable(
+		TickBand{From: decimal.Zero, Increment: decimal.New(1, -2)},
+		TickBand{From: decimal.New(1, 0), Increment: decimal.New(5, -2)},
+		TickBand{From: decimal.New(10, 0), Increment: decimal.New(1, -1)},
+	)
+	assert.Nil(t, err)
+	return table
+}
+
+func TestNewTickTable(t *testing.T) {
+
+	_, err := NewTickTable()
+	assert.NotNil(t, err)
+
+	_, err = NewTickTable(TickBand{Increment: decimal.Zero})
+	assert.NotNil(t, err)
+
+	_, err = NewTickTable(
+		TickBand{From: decimal.New(1, 0), Increment: decimal.New(1, -2)},
+		TickBand{From: decimal.New(1, 0), Increment: decimal.New(1, -1)},
+	)
+	assert.NotNil(t, err)
+
+	table := testTickTable(t)
+	assert.Equal(t, 3, len(table.Bands()))
+	assert.Equal(t, int32(2), table.Precision())
+
+}
+
+func TestTickTableIncrement(t *testing.T) {
+
+	table := testTickTable(t)
+
+	assert.True(t, table.Increment(decimal.New(99, -2)).Equal(decimal.New(1, -2)))
+	assert.True(t, table.Increment(decimal.New(1, 0)).Equal(decimal.New(5, -2)))
+	assert.True(t, table.Increment(decimal.New(10, 0)).Equal(decimal.New(1, -1)))
+
+	assert.True(t, table.Round(decimal.New(1234, -3)).Equal(decimal.New(120, -2)))
+	assert.True(t, table.RoundUp(decimal.New(1234, -3)).Equal(decimal.New(125, -2)))
+	assert.True(t, table.RoundUp(decimal.New(125, -2)).Equal(decimal.New(125, -2)))
+
+	//
+	// Below the first band, rounding is still down.
+	//
+	offset, err := NewTickTable(TickBand{From: decimal.New(1, 0), Increment: decimal.New(5, -1)})
+	assert.Nil(t, err)
+	assert.True(t, offset.Round(decimal.New(7, -1)).Equal(decimal.New(5, -1)))
+	assert.True(t, offset.Round(decimal.New(-2, -1)).Equal(decimal.New(-5, -1)))
+	assert.True(t, table.Round(decimal.New(-1234, -3)).Equal(decimal.New(-124, -2)))
+	assert.True(t, table.RoundUp(decimal.New(-1234, -3)).Equal(decimal.New(-123, -2)))
+
+}
+
+func TestTickTableUpDown(t *testing.T) {
+
+	table := testTickTable(t)
+
+	assert.True(t, table.Up(decimal.New(98, -2), 1).Equal(decimal.New(99, -2)))
+	assert.True(t, table.Up(decimal.New(99, -2), 1).Equal(decimal.New(1, 0)))
+	assert.True(t, table.Up(decimal.New(99, -2), 2).Equal(decimal.New(105, -2)))
+	assert.True(t, table.Up(decimal.New(995, -2), 2).Equal(decimal.New(101, -1)))
+	assert.True(t, table.Up(decimal.New(98, -2), -1).Equal(decimal.New(97, -2)))
+
+	assert.True(t, table.Down(decimal.New(105, -2), 1).Equal(decimal.New(1, 0)))
+	assert.True(t, table.Down(decimal.New(105, -2), 2).Equal(decimal.New(99, -2)))
+	assert.True(t, table.Down(decimal.New(101, -1), 2).Equal(decimal.New(995, -2)))
+	assert.True(t, table.Down(decimal.New(1, 0), 1).Equal(decimal.New(99, -2)))
+	assert.True(t, table.Down(decimal.New(1, 0), -1).Equal(decimal.New(105, -2)))
+
+}
+
+func TestTickTableTicks(t *testing.T) {
+
+	table := testTickTable(t)
+
+	assert.Equal(t, int64(0), table.Ticks(decimal.New(1, 0), decimal.New(1, 0)))
+	assert.Equal(t, int64(1), table.Ticks(decimal.New(99, -2), decimal.New(1, 0)))
+	assert.Equal(t, int64(2), table.Ticks(decimal.New(99, -2), decimal.New(105, -2)))
+	assert.Equal(t, int64(-2), table.Ticks(decimal.New(105, -2), decimal.New(99, -2)))
+
+	from := decimal.New(95, -2)
+	for n := int64(0); n < 250; n++ {
+		to := table.Up(from, n)
+		assert.Equal(t, n, table.Ticks(from, to))
+		assert.True(t, table.Down(to, n).Equal(from))
+	}
+
+}
+
+func TestTickTableJSON(t *testing.T) {
+
+	listing := &Listing{Symbol: "A", TickTable: testTickTable(t)}
+	b, err := json.Marshal(listing)
+	assert.Nil(t, err)
+
+	var decoded Listing
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	bands := decoded.TickTable.Bands()
+	assert.Equal(t, 3, len(bands))
+	for i, band := range listing.TickTable.Bands() {
+		assert.True(t, band.From.Equal(bands[i].From))
+		assert.True(t, band.Increment.Equal(bands[i].Increment))
+	}
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"TickTable":[{"from":"1","increment":"0"}]}`), &decoded))
+
+	b, err = json.Marshal(&Listing{Symbol: "B"})
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "TickTable")
+
+}
+
+func TestListingTicks(t *testing.T) {
+
+	flat := &Listing{TickIncrement: decimal.New(5, -1)}
+	assert.True(t, flat.TickAt(decimal.New(100, 0)).Equal(decimal.New(5, -1)))
+	assert.True(t, flat.RoundPrice(decimal.New(1007, -1)).Equal(decimal.New(1005, -1)))
+	assert.True(t, flat.TickUp(decimal.New(100, 0), 3).Equal(decimal.New(1015, -1)))
+	assert.True(t, flat.TickDown(decimal.New(100, 0), 3).Equal(decimal.New(985, -1)))
+	assert.Equal(t, int64(4), flat.Ticks(decimal.New(100, 0), decimal.New(102, 0)))
+	assert.Equal(t, int32(1), flat.PricePrecision())
+	assert.True(t, flat.RoundPrice(decimal.New(-1007, -1)).Equal(decimal.New(-101, 0)))
+	assert.True(t, flat.TickUp(decimal.New(1007, -1), -1).Equal(decimal.New(1005, -1)))
+	assert.True(t, flat.TickDown(decimal.New(1007, -1), -1).Equal(decimal.New(101, 0)))
+	assert.Equal(t, int64(-4), flat.Ticks(decimal.New(102, 0), decimal.New(100, 0)))
+
+	banded := &Listing{TickIncrement: decimal.New(1, 0), TickTable: testTickTable(t)}
+	assert.True(t, banded.TickAt(decimal.New(5, 0)).Equal(decimal.New(5, -2)))
+	assert.True(t, banded.RoundPrice(decimal.New(503, -2)).Equal(decimal.New(5, 0)))
+	assert.True(t, banded.TickUp(decimal.New(995, -2), 1).Equal(decimal.New(10, 0)))
+	assert.Equal(t, int32(2), banded.PricePrecision())
+
+	none := &Listing{}
+	assert.True(t, none.RoundPrice(decimal.New(1234, -3)).Equal(decimal.New(1234, -3)))
+	assert.True(t, none.TickUp(decimal.New(1, 0), 1).Equal(decimal.New(1, 0)))
+
+}
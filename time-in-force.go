@@ -2,6 +2,7 @@ package mkt
 
 import (
 	"encoding/json"
+	"fmt"
 	"sort"
 
 	"github.com/quickfixgo/enum"
@@ -51,6 +52,20 @@ func TimeInForceFromString(s string) TimeInForce {
 	}
 }
 
+// ParseTimeInForce returns the [TimeInForce] for the mnemonic, the long name
+// or the FIX code. Unlike [TimeInForceFromString] it returns an error for any
+// other value.
+func ParseTimeInForce(s string) (TimeInForce, error) {
+	switch s {
+	case "GTC", "GOOD_TILL_CANCEL", string(enum.TimeInForce_GOOD_TILL_CANCEL):
+		return GTC, nil
+	case "IOC", "IMMEDIATE_OR_CANCEL", string(enum.TimeInForce_IMMEDIATE_OR_CANCEL):
+		return IOC, nil
+	default:
+		return 0, fmt.Errorf("mkt.ParseTimeInForce: unknown value %q", s)
+	}
+}
+
 // MarshalJSON implements json.Marshaler.
 func (x TimeInForce) MarshalJSON() ([]byte, error) {
 	return json.Marshal(x.String())
@@ -80,6 +95,19 @@ func (x TimeInForce) AsQuickFIX() field.TimeInForceField {
 	}
 }
 
+// TimeInForceFromFIX returns the equivalent [TimeInForce] from the QuickFIX
+// field, or zero if there is no equivalence.
+func TimeInForceFromFIX(timeInForce field.TimeInForceField) TimeInForce {
+	switch timeInForce.Value() {
+	case enum.TimeInForce_GOOD_TILL_CANCEL:
+		return GTC
+	case enum.TimeInForce_IMMEDIATE_OR_CANCEL:
+		return IOC
+	default:
+		return 0
+	}
+}
+
 // HavingTimeInForce is the interface required for [SortImmediateFirst].
 type HavingTimeInForce interface {
 	TimeInForce() TimeInForce
@@ -18,3 +18,9 @@ func TestSortTimeInForce(t *testing.T) {
 	assert.Equal(t, "C", orders[0].orderID)
 
 }
+
+func TestTimeInForceFromFIX(t *testing.T) {
+	assert.Equal(t, GTC, TimeInForceFromFIX(GTC.AsQuickFIX()))
+	assert.Equal(t, IOC, TimeInForceFromFIX(IOC.AsQuickFIX()))
+	assert.Equal(t, TimeInForce(0), TimeInForceFromFIX(TimeInForce(0).AsQuickFIX()))
+}
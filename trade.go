@@ -47,3 +47,15 @@ func (x *Trade) Aggregate(trade *Trade, precision int32) {
 // TradeKey is a convenience function to use when constructing a
 // [utl.ConflatingQueue] for [Trade].
 func TradeKey(trade *Trade) string { return trade.Symbol }
+
+// ZeroTrade will 'zero' all the fields in the [*Trade], never returning nil.
+// This is a convenience for using trades in a [utl.Pool].
+func ZeroTrade(trade *Trade) *Trade {
+	if trade == nil {
+		return &Trade{}
+	}
+	trade.Symbol = ""
+	trade.LastQty, trade.LastPx = decimal.Zero, decimal.Zero
+	trade.TradeVolume, trade.AvgPx = decimal.Zero, decimal.Zero
+	return trade
+}
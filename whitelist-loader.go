@@ -0,0 +1,220 @@
+package mkt
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// The CSV columns read into [Listing] by [LoadWhiteListCSV]. Column names are
+// not case sensitive.
+const (
+	ColumnSymbol             = "symbol"
+	ColumnTickIncrement      = "tickincrement"
+	ColumnRoundLot           = "roundlot"
+	ColumnMinTradeVol        = "mintradevol"
+	ColumnContractMultiplier = "contractmultiplier"
+)
+
+// LoadWhiteListCSV reads listings from CSV with a header row and returns them
+// in a new [*WhiteList]. The function argument returns a new, empty T; any
+// columns other than those read into [Listing] are passed to the extra
+// function, which may be nil, for setting fields on types embedding
+// [Listing]. A column in the header but missing from a row is an error.
+//
+// Every row is validated by [ValidateListing] and a missing contract
+// multiplier defaults to one. Errors report the line number.
+func LoadWhiteListCSV[T AnyListing](r io.Reader, newListing func() T, extra func(listing T, column, value string) error) (*WhiteList[T], error) {
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("mkt.LoadWhiteListCSV: reading header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+
+	loaded := &loading[T]{symbols: map[string]bool{}}
+
+	for {
+
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return loaded.whitelist(), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mkt.LoadWhiteListCSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		listing := newListing()
+		def := listing.Definition()
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if err := setListingColumn(listing, def, header[i], value, extra); err != nil {
+				return nil, fmt.Errorf("mkt.LoadWhiteListCSV: line %d: %s: %w", line, header[i], err)
+			}
+		}
+
+		if err := loaded.add(listing); err != nil {
+			return nil, fmt.Errorf("mkt.LoadWhiteListCSV: line %d: %w", line, err)
+		}
+
+	}
+
+}
+
+func setListingColumn[T AnyListing](listing T, def *Listing, column, value string, extra func(T, string, string) error) error {
+
+	parse := func(target *decimal.Decimal) error {
+		if value == "" {
+			*target = decimal.Zero
+			return nil
+		}
+		d, err := decimal.NewFromString(value)
+		if err != nil {
+			return err
+		}
+		*target = d
+		return nil
+	}
+
+	switch column {
+	case ColumnSymbol:
+		def.Symbol = value
+		return nil
+	case ColumnTickIncrement:
+		return parse(&def.TickIncrement)
+	case ColumnRoundLot:
+		return parse(&def.RoundLot)
+	case ColumnMinTradeVol:
+		return parse(&def.MinTradeVol)
+	case ColumnContractMultiplier:
+		return parse(&def.ContractMultiplier)
+	default:
+		if extra == nil {
+			return nil
+		}
+		return extra(listing, column, value)
+	}
+
+}
+
+// LoadWhiteListJSON reads listings from either a JSON array of objects or
+// JSON lines, and returns them in a new [*WhiteList]. Each object is decoded
+// into a new T returned by the function argument, so types embedding
+// [Listing] receive their own fields too. Field names are matched as by
+// [json.Unmarshal], so "symbol" and "tickIncrement" are accepted.
+//
+// Every listing is validated as by [LoadWhiteListCSV]. Errors report the line
+// number on which the listing starts.
+func LoadWhiteListJSON[T AnyListing](r io.Reader, newListing func() T) (*WhiteList[T], error) {
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("mkt.LoadWhiteListJSON: %w", err)
+	}
+
+	loaded := &loading[T]{symbols: map[string]bool{}}
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return loaded.whitelist(), nil
+	}
+	array := trimmed[0] == '['
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("mkt.LoadWhiteListJSON: %w", err)
+		}
+	}
+
+	for dec.More() {
+
+		line := lineAt(data, dec.InputOffset())
+		listing := newListing()
+		if err := dec.Decode(listing); err != nil {
+			return nil, fmt.Errorf("mkt.LoadWhiteListJSON: line %d: %w", line, err)
+		}
+		if err := loaded.add(listing); err != nil {
+			return nil, fmt.Errorf("mkt.LoadWhiteListJSON: line %d: %w", line, err)
+		}
+
+	}
+
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("mkt.LoadWhiteListJSON: %w", err)
+		}
+	}
+	return loaded.whitelist(), nil
+
+}
+
+// lineAt returns the line number of the first value at or after the offset,
+// skipping whitespace and array separators.
+func lineAt(data []byte, offset int64) int {
+	i := int(offset)
+	for i < len(data) && strings.IndexByte(" \t\r\n,", data[i]) >= 0 {
+		i++
+	}
+	return 1 + bytes.Count(data[:i], []byte{'\n'})
+}
+
+// ValidateListing returns an error if the listing cannot be traded: the
+// symbol is empty or any increment is negative.
+func ValidateListing(listing *Listing) error {
+	if strings.TrimSpace(listing.Symbol) == "" {
+		return errors.New("empty symbol")
+	}
+	if listing.TickIncrement.IsNegative() {
+		return fmt.Errorf("%s: negative tick increment", listing.Symbol)
+	}
+	if listing.RoundLot.IsNegative() {
+		return fmt.Errorf("%s: negative round lot", listing.Symbol)
+	}
+	if listing.MinTradeVol.IsNegative() {
+		return fmt.Errorf("%s: negative min trade vol", listing.Symbol)
+	}
+	if listing.ContractMultiplier.IsNegative() {
+		return fmt.Errorf("%s: negative contract multiplier", listing.Symbol)
+	}
+	return nil
+}
+
+// loading collects validated listings, to be published all at once.
+type loading[T AnyListing] struct {
+	listings []T
+	symbols  map[string]bool
+}
+
+func (x *loading[T]) add(listing T) error {
+	def := listing.Definition()
+	if err := ValidateListing(def); err != nil {
+		return err
+	}
+	if x.symbols[def.Symbol] {
+		return fmt.Errorf("%s: duplicate symbol", def.Symbol)
+	}
+	if def.ContractMultiplier.IsZero() {
+		def.ContractMultiplier = DecimalOne
+	}
+	x.symbols[def.Symbol] = true
+	x.listings = append(x.listings, listing)
+	return nil
+}
+
+func (x *loading[T]) whitelist() *WhiteList[T] {
+	whitelist := NewWhiteList[T]()
+	whitelist.Replace(x.listings)
+	return whitelist
+}
@@ -0,0 +1,92 @@
+package mkt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type sectorListing struct {
+	Listing
+	Sector string
+}
+
+func TestLoadWhiteListCSV(t *testing.T) {
+
+	data := `Symbol,TickIncrement,RoundLot,MinTradeVol,ContractMultiplier,Sector
+A,0.01,100,100,,Energy
+B, 0.5 ,1,1,50,Rates
+`
+	whitelist, err := LoadWhiteListCSV(
+		strings.NewReader(data),
+		func() *sectorListing { return &sectorListing{} },
+		func(listing *sectorListing, column, value string) error {
+			if column == "sector" {
+				listing.Sector = value
+			}
+			return nil
+		},
+	)
+	assert.Nil(t, err)
+
+	a, ok := whitelist.Lookup("A")
+	assert.True(t, ok)
+	assert.True(t, a.TickIncrement.Equal(decimal.New(1, -2)))
+	assert.True(t, a.ContractMultiplier.Equal(DecimalOne), "default")
+	assert.Equal(t, "Energy", a.Sector)
+
+	b, ok := whitelist.Lookup("B")
+	assert.True(t, ok)
+	assert.True(t, b.TickIncrement.Equal(decimal.New(5, -1)))
+	assert.True(t, b.ContractMultiplier.Equal(decimal.New(50, 0)))
+
+	cases := []struct {
+		desc string
+		data string
+		line string
+	}{
+		{desc: "bad decimal", data: "symbol,tickincrement\nA,0.01\nB,x\n", line: "line 3"},
+		{desc: "empty symbol", data: "symbol,tickincrement\n,0.01\n", line: "line 2"},
+		{desc: "negative", data: "symbol,roundlot\nA,-1\n", line: "line 2"},
+		{desc: "duplicate", data: "symbol\nA\nB\nA\n", line: "line 4"},
+		{desc: "short row", data: "symbol,roundlot\nA\n", line: "line 2"},
+	}
+	for _, c := range cases {
+		_, err := LoadWhiteListCSV(strings.NewReader(c.data), func() *Listing { return &Listing{} }, nil)
+		if assert.NotNil(t, err, c.desc) {
+			assert.Contains(t, err.Error(), c.line, c.desc)
+		}
+	}
+
+}
+
+func TestLoadWhiteListJSON(t *testing.T) {
+
+	array := `[
+  {"symbol": "A", "tickIncrement": "0.01", "roundLot": 100, "sector": "Energy"},
+  {"symbol": "B", "tickIncrement": "0.5", "contractMultiplier": 50}
+]`
+	whitelist, err := LoadWhiteListJSON(strings.NewReader(array), func() *sectorListing { return &sectorListing{} })
+	assert.Nil(t, err)
+	a, ok := whitelist.Lookup("A")
+	assert.True(t, ok)
+	assert.Equal(t, "Energy", a.Sector)
+	assert.True(t, a.RoundLot.Equal(decimal.New(100, 0)))
+
+	lines := `{"symbol": "A", "tickIncrement": "0.01"}
+
+{"symbol": "B", "tickIncrement": "-0.5"}
+`
+	_, err = LoadWhiteListJSON(strings.NewReader(lines), func() *Listing { return &Listing{} })
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "line 3")
+	}
+
+	_, err = LoadWhiteListJSON(strings.NewReader("[\n{\"symbol\": \"A\"},\n{\"symbol\": 1}\n]"), func() *Listing { return &Listing{} })
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "line 3")
+	}
+
+}
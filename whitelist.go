@@ -1,13 +1,25 @@
 package mkt
 
 import (
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+
 	"github.com/gbkr-com/utl"
 )
 
 // A WhiteList has one or more T which can be traded.
+//
+// A WhiteList is safe for concurrent use. The default map is never modified
+// once published: writers copy it and atomically swap in the copy, so
+// [WhiteList.Lookup] takes no lock and readers never see a partly loaded
+// white list. Writes are therefore relatively expensive; load many listings
+// at once with [WhiteList.Replace].
 type WhiteList[T AnyListing] struct {
-	listings map[string]T          // Default map
-	cache    *utl.Cache[string, T] // Optional cache
+	listings atomic.Pointer[map[string]T] // Default map, copied on write.
+	lock     sync.Mutex                   // Serialises writers.
+	cache    *utl.Cache[string, T]        // Optional cache
 }
 
 // WhiteListOption is any option that be applied when the [*WhiteList] is manufactured.
@@ -21,6 +33,14 @@ func WithWhiteListCacheOption[T AnyListing](cache *utl.Cache[string, T]) WhiteLi
 	}
 }
 
+// WhiteListDiff is the difference made by [WhiteList.Replace], as sorted
+// symbols.
+type WhiteListDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
 // NewWhiteList returns a new [*WhiteList] ready to use.
 func NewWhiteList[T AnyListing](options ...WhiteListOption[T]) *WhiteList[T] {
 	whitelist := &WhiteList[T]{}
@@ -28,7 +48,8 @@ func NewWhiteList[T AnyListing](options ...WhiteListOption[T]) *WhiteList[T] {
 		option(whitelist)
 	}
 	if whitelist.cache == nil {
-		whitelist.listings = make(map[string]T)
+		listings := make(map[string]T)
+		whitelist.listings.Store(&listings)
 	}
 	return whitelist
 }
@@ -44,7 +65,7 @@ func (x *WhiteList[T]) Lookup(symbol string) (T, bool) {
 		}
 		return result, true
 	}
-	result, ok := x.listings[symbol]
+	result, ok := (*x.listings.Load())[symbol]
 	if !ok {
 		return empty, false
 	}
@@ -57,6 +78,119 @@ func (x *WhiteList[T]) Add(listing T) {
 	if x.cache != nil {
 		return
 	}
-	x.listings[listing.Definition().Symbol] = listing
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	listings := x.clone()
+	listings[listing.Definition().Symbol] = listing
+	x.listings.Store(&listings)
+
+}
+
+// Remove the listing for the symbol from this white list. If the cache option
+// was specified then this is a no-op.
+func (x *WhiteList[T]) Remove(symbol string) {
+	if x.cache != nil {
+		return
+	}
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	if _, ok := (*x.listings.Load())[symbol]; !ok {
+		return
+	}
+	listings := x.clone()
+	delete(listings, symbol)
+	x.listings.Store(&listings)
+}
+
+func (x *WhiteList[T]) clone() map[string]T {
+	current := *x.listings.Load()
+	listings := make(map[string]T, len(current)+1)
+	for symbol, listing := range current {
+		listings[symbol] = listing
+	}
+	return listings
+}
+
+// Len returns the number of listings in this white list. If the cache option
+// was specified this is always zero.
+func (x *WhiteList[T]) Len() int {
+	if x.cache != nil {
+		return 0
+	}
+	return len(*x.listings.Load())
+}
+
+// Symbols returns the sorted symbols in this white list. If the cache option
+// was specified this is always empty.
+func (x *WhiteList[T]) Symbols() []string {
+	if x.cache != nil {
+		return nil
+	}
+	listings := *x.listings.Load()
+	symbols := make([]string, 0, len(listings))
+	for symbol := range listings {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// ForEach visits every listing in this white list, in symbol order. If the
+// cache option was specified there is nothing to visit.
+func (x *WhiteList[T]) ForEach(visitor func(T)) {
+	if x.cache != nil {
+		return
+	}
+	listings := *x.listings.Load()
+	symbols := make([]string, 0, len(listings))
+	for symbol := range listings {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		visitor(listings[symbol])
+	}
+}
+
+// Replace the whole content of this white list with the listings, returning
+// the difference. The swap is atomic: [WhiteList.Lookup] sees either the old
+// or the new listings, never a mixture. A listing has changed if it is not
+// deeply equal to its predecessor. If the cache option was specified then
+// this is a no-op.
+func (x *WhiteList[T]) Replace(listings []T) *WhiteListDiff {
+
+	diff := &WhiteListDiff{}
+	if x.cache != nil {
+		return diff
+	}
+
+	next := make(map[string]T, len(listings))
+	for _, listing := range listings {
+		next[listing.Definition().Symbol] = listing
+	}
+
+	x.lock.Lock()
+	previous := *x.listings.Swap(&next)
+	x.lock.Unlock()
+
+	for symbol, listing := range next {
+		before, ok := previous[symbol]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, symbol)
+		case !reflect.DeepEqual(before, listing):
+			diff.Changed = append(diff.Changed, symbol)
+		}
+	}
+	for symbol := range previous {
+		if _, ok := next[symbol]; !ok {
+			diff.Removed = append(diff.Removed, symbol)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+
+	return diff
 
 }
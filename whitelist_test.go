@@ -1,6 +1,8 @@
 package mkt
 
 import (
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -41,3 +43,137 @@ func TestWhiteListWithCacheOption(t *testing.T) {
 	assert.Nil(t, listing)
 
 }
+
+func TestWhiteListEnumerate(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "B"})
+	whitelist.Add(&Listing{Symbol: "A"})
+	whitelist.Add(&Listing{Symbol: "C"})
+
+	assert.Equal(t, 3, whitelist.Len())
+	assert.Equal(t, []string{"A", "B", "C"}, whitelist.Symbols())
+
+	whitelist.Remove("B")
+	_, ok := whitelist.Lookup("B")
+	assert.False(t, ok)
+
+	var visited []string
+	whitelist.ForEach(func(listing *Listing) { visited = append(visited, listing.Symbol) })
+	assert.Equal(t, []string{"A", "C"}, visited)
+
+}
+
+func TestWhiteListReplace(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A", TickIncrement: decimal.New(1, -2)})
+	whitelist.Add(&Listing{Symbol: "B", TickIncrement: decimal.New(1, -2)})
+	whitelist.Add(&Listing{Symbol: "C", TickIncrement: decimal.New(1, -2)})
+
+	diff := whitelist.Replace([]*Listing{
+		{Symbol: "A", TickIncrement: decimal.New(1, -2)},
+		{Symbol: "B", TickIncrement: decimal.New(5, -3)},
+		{Symbol: "D", TickIncrement: decimal.New(1, -2)},
+	})
+
+	assert.Equal(t, []string{"D"}, diff.Added)
+	assert.Equal(t, []string{"C"}, diff.Removed)
+	assert.Equal(t, []string{"B"}, diff.Changed)
+
+	assert.Equal(t, 3, whitelist.Len())
+	b, ok := whitelist.Lookup("B")
+	assert.True(t, ok)
+	assert.True(t, b.TickIncrement.Equal(decimal.New(5, -3)))
+
+}
+
+func TestWhiteListConcurrent(t *testing.T) {
+
+	whitelist := NewWhiteList[*Listing]()
+	whitelist.Add(&Listing{Symbol: "A", ContractMultiplier: DecimalOne})
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			position := NewPosition("A", whitelist)
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				_, ok := whitelist.Lookup("A")
+				assert.True(t, ok)
+				position.Traded(Buy, DecimalOne, DecimalOne)
+				whitelist.Len()
+				whitelist.ForEach(func(*Listing) {})
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		symbol := strconv.Itoa(i)
+		whitelist.Add(&Listing{Symbol: symbol})
+		whitelist.Remove(symbol)
+		if i%50 == 0 {
+			whitelist.Replace([]*Listing{{Symbol: "A", ContractMultiplier: DecimalOne}})
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	assert.Equal(t, []string{"A"}, whitelist.Symbols())
+
+}
+
+func BenchmarkWhiteListLookup(b *testing.B) {
+
+	whitelist := NewWhiteList[*Listing]()
+	for i := 0; i < 1000; i++ {
+		whitelist.Add(&Listing{Symbol: strconv.Itoa(i)})
+	}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			whitelist.Lookup("500")
+		}
+	})
+
+}
+
+func BenchmarkWhiteListLookupDuringWrites(b *testing.B) {
+
+	whitelist := NewWhiteList[*Listing]()
+	for i := 0; i < 1000; i++ {
+		whitelist.Add(&Listing{Symbol: strconv.Itoa(i)})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				whitelist.Add(&Listing{Symbol: "X"})
+				whitelist.Remove("X")
+			}
+		}
+	}()
+	defer close(done)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			whitelist.Lookup("500")
+		}
+	})
+
+}